@@ -0,0 +1,60 @@
+package accounts
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/client-library/domain"
+)
+
+// bicPattern accepts a BIC8 or BIC11 in either case, as the directory
+// upper cases it.
+var bicPattern = regexp.MustCompile(`^[A-Za-z]{6}[A-Za-z0-9]{2}([A-Za-z0-9]{3})?$`)
+
+// LookupBank returns the banks listed under a BIC in the configured bank
+// directory. Without a directory it returns a 501 *APIError, which is not
+// retried: the lookup cannot succeed until one is configured. A malformed BIC
+// is a 400.
+func (c *Client) LookupBank(bic string) ([]domain.Bank, error) {
+	if c.config.BankDirectory == nil {
+		return nil, &APIError{StatusCode: http.StatusNotImplemented, Message: "bank directory not configured", Operation: OperationBank}
+	}
+	if !bicPattern.MatchString(bic) {
+		return nil, &APIError{StatusCode: http.StatusBadRequest, Message: "bic must be 8 or 11 characters: " + bic, Operation: OperationBank}
+	}
+
+	banks, ok := c.config.BankDirectory.Lookup(bic)
+	if !ok {
+		return nil, &APIError{StatusCode: http.StatusNotFound, Message: "bic " + bic + " does not exist", Operation: OperationBank}
+	}
+	return banks, nil
+}
+
+// enrichBank adds the directory entry of the account's BIC and bank ID to a fetch.
+func (c *Client) enrichBank(result *domain.GetAccountByIdResult) {
+	if c.config.BankDirectory == nil || len(result.Attributes.Bic) == 0 {
+		return
+	}
+
+	bank, ok := c.config.BankDirectory.Find(result.Attributes.Bic, result.Attributes.BankID)
+	if !ok {
+		bank, ok = c.config.BankDirectory.Find(result.Attributes.Bic, "")
+	}
+	if ok {
+		result.Bank = &bank
+	}
+}
+
+// bankCheck rejects creates whose BIC is unknown, belongs to another country
+// or does not list the bank_id.
+func (c *Client) bankCheck(attributes domain.Attributes) []domain.FieldError {
+	if c.config.BankDirectory == nil || len(attributes.Bic) == 0 {
+		return nil
+	}
+
+	if err := c.config.BankDirectory.Validate(attributes.Bic, attributes.Country, attributes.BankID); err != nil {
+		return []domain.FieldError{{Field: "bic", Rule: domain.RuleInvalid, Value: attributes.Bic,
+			Message: "bic in body is not consistent with the bank directory: " + err.Error()}}
+	}
+	return nil
+}
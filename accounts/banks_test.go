@@ -0,0 +1,66 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/client-library/bankdir"
+	"github.com/client-library/domain"
+)
+
+var testDirectory = bankdir.New([]domain.Bank{
+	{Bic: "NWBKGB22", Country: "GB", BankID: "400300", BankIDCode: "GBDSC", Name: "National Westminster Bank", Branch: "London City"}})
+
+func TestClient_FetchEnrichesBank(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"` + accountId + `","attributes":{"country":"GB","bank_id":"400300","bic":"NWBKGB22"}}}`))
+	})
+	client = NewClient(WithConfig(client.config), WithBankDirectory(testDirectory))
+
+	result, err := client.Fetch(context.Background(), accountId)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if result.Bank == nil || result.Bank.Branch != "London City" {
+		t.Errorf("Expected bank details, returned %+v", result.Bank)
+	}
+}
+
+func TestClient_CreateRejectsInconsistentBic(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	client = NewClient(WithConfig(client.config), WithBankDirectory(testDirectory))
+
+	_, err := client.Create(context.Background(), domain.CreateAccountRequest{
+		OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+		Attributes:     domain.Attributes{Country: "GB", BankID: "601613", Bic: "NWBKGB22", Name: []string{"Fábio"}},
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || len(apiErr.FieldErrors) != 1 || apiErr.FieldErrors[0].Field != "bic" {
+		t.Errorf("Expected bic error, returned %v", err)
+	}
+}
+
+func TestClient_LookupBank(t *testing.T) {
+	client := NewClient(WithBankDirectory(testDirectory))
+
+	if banks, err := client.LookupBank("NWBKGB22XXX"); err != nil || len(banks) != 1 {
+		t.Errorf("Expected 1 bank, returned %v %v", banks, err)
+	}
+	if _, err := client.LookupBank("BARCGB22"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %v, returned %v", ErrNotFound, err)
+	}
+	if _, err := client.LookupBank("NWBK"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected %v, returned %v", ErrValidation, err)
+	}
+
+	var apiErr *APIError
+	_, err := NewClient().LookupBank("NWBKGB22")
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented || errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected 501 bank directory not configured, returned %v", err)
+	}
+}
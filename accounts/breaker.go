@@ -0,0 +1,131 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the account API while the circuit
+// breaker is open. It matches ErrUnavailable.
+var ErrCircuitOpen = fmt.Errorf("%w: circuit breaker open", ErrUnavailable)
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = time.Duration(10) * time.Second
+)
+
+// CircuitBreaker stops calling the account API after Threshold consecutive
+// failed calls, a call failing when its last attempt ends in a transport error
+// or a 5xx response. Once Cooldown has passed it lets
+// a single probe through (half-open), closing again when the probe succeeds.
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	now      func() time.Time
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		now:       time.Now,
+		state:     BreakerClosed,
+	}
+}
+
+// State returns the current state, half-open once the cooldown of an open breaker has passed.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current()
+}
+
+func (b *CircuitBreaker) current() BreakerState {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.Cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow returns ErrCircuitOpen unless the request may go upstream. In the
+// half-open state only one probe is in flight at a time.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.current() {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+	}
+	return nil
+}
+
+// record counts the outcome of a request let through by allow.
+func (b *CircuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.Threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// release gives up a request let through by allow without counting it.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// done counts the outcome of a call let through by allow, once its retries are
+// over. Calls the caller cancelled or ran out of time for are not counted, nor
+// are failures that never reached the account API.
+func (b *CircuitBreaker) done(ctx context.Context, err error) {
+	var apiErr *APIError
+	var transportErr *TransportError
+	switch {
+	case err == nil:
+		b.record(true)
+	case ctx.Err() != nil:
+		b.release()
+	case errors.As(err, &apiErr):
+		b.record(apiErr.StatusCode < 500)
+	case errors.As(err, &transportErr):
+		b.record(false)
+	default:
+		b.release()
+	}
+}
@@ -0,0 +1,206 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingServer answers 503 while failing is set, and a fetched account otherwise.
+// The client makes a single attempt unless opts say otherwise.
+func failingServer(t *testing.T, breaker *CircuitBreaker, opts ...Option) (*Client, *int32, *int32) {
+	var requests, failing int32 = 0, 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fetchHandler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{WithBaseURL(server.URL), WithRetryPolicy(NoRetry), WithCircuitBreaker(breaker)}, opts...)
+	return NewClient(opts...), &requests, &failing
+}
+
+// testBreaker returns a breaker whose clock is moved forward by advance.
+func testBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, func(time.Duration)) {
+	breaker := NewCircuitBreaker(threshold, cooldown)
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+	return breaker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	breaker, _ := testBreaker(3, time.Minute)
+	client, requests, _ := failingServer(t, breaker)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Fetch(context.Background(), accountId); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected the breaker to be closed on attempt %d", i+1)
+		}
+	}
+	if state := client.BreakerState(); state != BreakerOpen {
+		t.Fatalf("Expected %s, returned %s", BreakerOpen, state)
+	}
+
+	_, err := client.Fetch(context.Background(), accountId)
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrCircuitOpen matching ErrUnavailable, returned %v", err)
+	}
+	if *requests != 3 {
+		t.Errorf("Expected %d requests upstream, returned %d", 3, *requests)
+	}
+}
+
+func TestCircuitBreaker_CountsCallsNotAttempts(t *testing.T) {
+	breaker, _ := testBreaker(2, time.Minute)
+	client, requests, _ := failingServer(t, breaker, WithRetryPolicy(fastRetry))
+
+	client.Fetch(context.Background(), accountId)
+	if state := client.BreakerState(); state != BreakerClosed {
+		t.Fatalf("Expected %s after one call, returned %s", BreakerClosed, state)
+	}
+	client.Fetch(context.Background(), accountId)
+	if state := client.BreakerState(); state != BreakerOpen {
+		t.Errorf("Expected %s after two calls, returned %s", BreakerOpen, state)
+	}
+	if *requests != 6 {
+		t.Errorf("Expected %d requests upstream, returned %d", 6, *requests)
+	}
+}
+
+func TestCircuitBreaker_IgnoresCallerCancellation(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		context func() (context.Context, context.CancelFunc)
+	}{
+		{"Canceled", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+			return ctx, cancel
+		}},
+		{"DeadlineExceeded", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 20*time.Millisecond)
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			}))
+			t.Cleanup(server.Close)
+
+			breaker, _ := testBreaker(1, time.Minute)
+			client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(fastRetry), WithCircuitBreaker(breaker))
+
+			ctx, cancel := tc.context()
+			defer cancel()
+			if _, err := client.Fetch(ctx, accountId); err == nil {
+				t.Fatal("Expected the call to fail")
+			}
+
+			if state := client.BreakerState(); state != BreakerClosed {
+				t.Errorf("Expected %s, returned %s", BreakerClosed, state)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	breaker, _ := testBreaker(2, time.Minute)
+	client, _, failing := failingServer(t, breaker)
+
+	client.Fetch(context.Background(), accountId)
+	atomic.StoreInt32(failing, 0)
+	client.Fetch(context.Background(), accountId)
+	atomic.StoreInt32(failing, 1)
+	client.Fetch(context.Background(), accountId)
+
+	if state := client.BreakerState(); state != BreakerClosed {
+		t.Errorf("Expected %s, returned %s", BreakerClosed, state)
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		probe_fails    int32
+		expected_state BreakerState
+	}{
+		{"ProbeSucceeds", 0, BreakerClosed},
+		{"ProbeFails", 1, BreakerOpen},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			breaker, advance := testBreaker(1, time.Minute)
+			client, requests, failing := failingServer(t, breaker)
+
+			client.Fetch(context.Background(), accountId)
+			advance(time.Minute)
+			if state := client.BreakerState(); state != BreakerHalfOpen {
+				t.Fatalf("Expected %s, returned %s", BreakerHalfOpen, state)
+			}
+
+			atomic.StoreInt32(failing, tc.probe_fails)
+			client.Fetch(context.Background(), accountId)
+
+			if state := client.BreakerState(); state != tc.expected_state {
+				t.Errorf("Expected %s, returned %s", tc.expected_state, state)
+			}
+			if *requests != 2 {
+				t.Errorf("Expected %d requests upstream, returned %d", 2, *requests)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_SingleProbe(t *testing.T) {
+	breaker, advance := testBreaker(1, time.Minute)
+	breaker.allow()
+	breaker.record(false)
+	advance(time.Minute)
+
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("Expected the probe to be allowed, returned %v", err)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a second request to fail fast while probing, returned %v", err)
+	}
+}
+
+func TestCircuitBreaker_ConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	breaker, _ := testBreaker(2, time.Minute)
+	client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(NoRetry), WithCircuitBreaker(breaker))
+
+	client.Fetch(context.Background(), accountId)
+	client.Fetch(context.Background(), accountId)
+
+	if _, err := client.Fetch(context.Background(), accountId); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, returned %v", err)
+	}
+}
+
+func TestCircuitBreaker_IgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	breaker, _ := testBreaker(1, time.Minute)
+	client := NewClient(WithBaseURL(server.URL), WithCircuitBreaker(breaker))
+
+	client.Fetch(context.Background(), accountId)
+
+	if state := client.BreakerState(); state != BreakerClosed {
+		t.Errorf("Expected %s, returned %s", BreakerClosed, state)
+	}
+}
@@ -0,0 +1,306 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/client-library/domain"
+
+	"github.com/google/uuid"
+)
+
+// Client calls the Form3 Account API and maps its JSON:API envelopes
+// to the domain result types.
+type Client struct {
+	config     Config
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client built from DefaultConfig and the given options.
+func NewClient(opts ...Option) *Client {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	transport := config.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Client{
+		config:     config,
+		baseURL:    config.accountsURL(),
+		httpClient: &http.Client{Transport: transport},
+	}
+}
+
+// AccountsURL returns the address of the organisation accounts resource,
+// e.g. http://localhost:8080/v1/organisation/accounts.
+func (c *Client) AccountsURL() string {
+	return c.baseURL
+}
+
+// BreakerState returns the state of the circuit breaker, closed when there is none.
+func (c *Client) BreakerState() BreakerState {
+	if c.config.Breaker == nil {
+		return BreakerClosed
+	}
+	return c.config.Breaker.State()
+}
+
+func (c *Client) Fetch(ctx context.Context, accountId string) (*domain.GetAccountByIdResult, error) {
+	backendResult, err := c.fetch(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	//map
+	var result domain.GetAccountByIdResult
+	result.Attributes = backendResult.Data.Attributes
+	result.CreatedOn = backendResult.Data.CreatedOn
+	result.Version = backendResult.Data.Version
+	c.enrichBank(&result)
+
+	return &result, nil
+}
+
+// fetch returns the account as the API sends it, version and all.
+func (c *Client) fetch(ctx context.Context, accountId string) (*domain.GetAccountByIdBackendResult, error) {
+	if err := checkAccountID(OperationFetch, accountId); err != nil {
+		return nil, err
+	}
+	body, err := c.do(ctx, OperationFetch, accountId, http.MethodGet, c.baseURL+"/"+accountId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var backendResult domain.GetAccountByIdBackendResult
+	if err := json.Unmarshal(body, &backendResult); err != nil {
+		return nil, err
+	}
+
+	return &backendResult, nil
+}
+
+// Create normalises and validates the request locally first, including the rules
+// of its country; an invalid request is returned as a 400 *APIError, matching
+// ErrValidation, without calling the account API.
+//
+// When the request carries an ID and that account already exists with the same
+// organisation and attributes, the stored account is returned instead of the
+// duplicate constraint error, so retried creates are idempotent.
+func (c *Client) Create(ctx context.Context, request domain.CreateAccountRequest) (*domain.CreateAccountResult, error) {
+	if c.config.CountryRules != nil {
+		request.Attributes = c.config.CountryRules.Normalise(request.Attributes)
+	}
+	fieldErrors := request.Validate()
+	var ibanErrors []domain.FieldError
+	request.Attributes, ibanErrors = reconcileIban(request.Attributes)
+	fieldErrors = append(fieldErrors, ibanErrors...)
+	if c.config.CountryRules != nil {
+		fieldErrors = append(fieldErrors, c.config.CountryRules.Validate(request.Attributes)...)
+	}
+	fieldErrors = append(fieldErrors, c.modulusCheck(request.Attributes)...)
+	fieldErrors = append(fieldErrors, c.bankCheck(request.Attributes)...)
+	if len(fieldErrors) > 0 {
+		return nil, &APIError{
+			StatusCode:  http.StatusBadRequest,
+			Message:     domain.ValidationMessage(fieldErrors),
+			Operation:   OperationCreate,
+			FieldErrors: fieldErrors,
+		}
+	}
+
+	requestBackend := &domain.CreateAccountBackendRequest{}
+	requestBackend.Data.ID = request.ID
+	if len(requestBackend.Data.ID) == 0 {
+		requestBackend.Data.ID = uuid.NewString()
+	}
+	requestBackend.Data.Type = "accounts"
+	requestBackend.Data.OrganisationID = request.OrganisationID
+	requestBackend.Data.Attributes = request.Attributes
+
+	accountJson, err := json.Marshal(requestBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	// A create is only retried when the caller chose the ID: a retry after a lost
+	// response then answers 409 and is resolved by existing below.
+	policy := c.config.retryPolicy(OperationCreate, len(request.ID) > 0)
+	body, err := c.send(ctx, OperationCreate, policy, requestBackend.Data.ID, http.MethodPost, c.baseURL, accountJson)
+	if errors.Is(err, ErrDuplicate) && len(request.ID) > 0 {
+		return c.existing(ctx, request, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backendResult domain.CreateAccountBackendResult
+	if err := json.Unmarshal(body, &backendResult); err != nil {
+		return nil, err
+	}
+
+	var result domain.CreateAccountResult
+	result.AccountId = backendResult.Data.ID
+	result.Attributes = backendResult.Data.Attributes
+	result.CreatedOn = backendResult.Data.CreatedOn
+
+	return &result, nil
+}
+
+// Update changes only the attributes set in patch. The version must match the stored
+// account, otherwise the error matches ErrVersionConflict.
+func (c *Client) Update(ctx context.Context, accountId string, version int64, patch domain.AttributesPatch) (*domain.AccountResult, error) {
+	if err := checkAccountID(OperationUpdate, accountId); err != nil {
+		return nil, err
+	}
+	requestBackend := &domain.UpdateAccountBackendRequest{}
+	requestBackend.Data.ID = accountId
+	requestBackend.Data.Type = "accounts"
+	requestBackend.Data.Version = version
+	requestBackend.Data.Attributes = patch
+
+	accountJson, err := json.Marshal(requestBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.do(ctx, OperationUpdate, accountId, http.MethodPatch, c.baseURL+"/"+accountId, accountJson)
+	if err != nil {
+		return nil, err
+	}
+
+	var backendResult domain.UpdateAccountBackendResult
+	if err := json.Unmarshal(body, &backendResult); err != nil {
+		return nil, err
+	}
+
+	result := toAccountResult(backendResult.Data)
+	return &result, nil
+}
+
+func (c *Client) Delete(ctx context.Context, accountId string, version int64) (*domain.DeleteAccountResult, error) {
+	if err := checkAccountID(OperationDelete, accountId); err != nil {
+		return nil, err
+	}
+	url := c.baseURL + "/" + accountId + "?version=" + strconv.FormatInt(version, 10)
+	if _, err := c.do(ctx, OperationDelete, accountId, http.MethodDelete, url, nil); err != nil {
+		return nil, err
+	}
+
+	var result domain.DeleteAccountResult
+	result.Message = "Account ID " + accountId + " removed with success"
+	result.Success = true
+
+	return &result, nil
+}
+
+// checkAccountID rejects an account ID that is not a UUID as a 400 *APIError
+// before anything is sent: an empty one would address the collection instead.
+func checkAccountID(operation Operation, accountId string) error {
+	if _, err := uuid.Parse(accountId); err != nil || len(accountId) != 36 {
+		return &APIError{StatusCode: http.StatusBadRequest, Message: "account_id must be a uuid: " + accountId,
+			Operation: operation, AccountID: accountId}
+	}
+	return nil
+}
+
+// do sends the request with the retry policy of the operation. Create and Update
+// are only retried when a policy is configured for them.
+func (c *Client) do(ctx context.Context, operation Operation, accountId string, method string, url string, body []byte) ([]byte, error) {
+	idempotent := operation != OperationCreate && operation != OperationUpdate
+	return c.send(ctx, operation, c.config.retryPolicy(operation, idempotent), accountId, method, url, body)
+}
+
+// send goes through the circuit breaker, which counts the call once whatever
+// the number of attempts, and returns the result of attempts.
+func (c *Client) send(ctx context.Context, operation Operation, policy RetryPolicy, accountId string, method string, url string, body []byte) ([]byte, error) {
+	breaker := c.config.Breaker
+	if breaker == nil {
+		return c.attempts(ctx, operation, policy, accountId, method, url, body)
+	}
+	if err := breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	responseBody, err := c.attempts(ctx, operation, policy, accountId, method, url, body)
+	breaker.done(ctx, err)
+	return responseBody, err
+}
+
+// attempts makes up to policy.MaxAttempts attempts, backing off between them,
+// and returns the response body when the status is 2xx, an *APIError carrying
+// the upstream status and error_message otherwise, or a *TransportError when no
+// response was received.
+func (c *Client) attempts(ctx context.Context, operation Operation, policy RetryPolicy, accountId string, method string, url string, body []byte) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("content-type", "application/json")
+		}
+		if len(c.config.UserAgent) > 0 {
+			req.Header.Set("user-agent", c.config.UserAgent)
+		}
+
+		responseBody, header, err := c.roundTrip(req, operation, accountId)
+		if err == nil {
+			return responseBody, nil
+		}
+
+		delay, retryable := policy.next(attempt, err, header)
+		if !retryable || ctx.Err() != nil {
+			return nil, err
+		}
+		if attempt >= policy.MaxAttempts {
+			if policy.MaxAttempts > 1 && c.config.Metrics != nil {
+				c.config.Metrics.RetriesExhausted(operation, err)
+			}
+			return nil, err
+		}
+
+		if c.config.Metrics != nil {
+			c.config.Metrics.Retry(operation, attempt+1, err)
+		}
+		if sleep(ctx, delay) != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTrip makes a single attempt within the timeout of the operation.
+func (c *Client) roundTrip(req *http.Request, operation Operation, accountId string) ([]byte, http.Header, error) {
+	if timeout := c.config.timeout(operation); timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, newTransportError(operation, accountId, err)
+	}
+
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, response.Header, newTransportError(operation, accountId, err)
+	}
+
+	statusOK := response.StatusCode >= 200 && response.StatusCode < 300
+	if !statusOK {
+		return nil, response.Header, newAPIError(operation, accountId, response.StatusCode, responseBody)
+	}
+
+	return responseBody, response.Header, nil
+}
@@ -0,0 +1,252 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/client-library/domain"
+)
+
+var accountId = "802052e6-182e-11ed-861d-0242ac120002"
+
+func stubServer(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient(WithBaseURL(server.URL))
+}
+
+func TestClient_Fetch(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/organisation/accounts/"+accountId {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"data":{"id":"` + accountId + `","version":3,"attributes":{"country":"GB","name":["Fábio"],` +
+			`"account_number":"41426819","iban":"GB11NWBK40030041426819","joint_account":false,"status":"confirmed"}}}`))
+	})
+
+	result, err := client.Fetch(context.Background(), accountId)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if result.Attributes.Country != "GB" {
+		t.Errorf("Expected %s, returned %s", "GB", result.Attributes.Country)
+	}
+	if result.Version != 3 {
+		t.Errorf("Expected %d, returned %d", 3, result.Version)
+	}
+	if result.Attributes.Iban != "GB11NWBK40030041426819" || result.Attributes.AccountNumber != "41426819" {
+		t.Errorf("Expected account identification, returned %+v", result.Attributes)
+	}
+	if result.Attributes.JointAccount == nil || *result.Attributes.JointAccount {
+		t.Errorf("Expected joint_account false, returned %v", result.Attributes.JointAccount)
+	}
+	if result.Attributes.Switched != nil {
+		t.Errorf("Expected switched to be unset, returned %v", *result.Attributes.Switched)
+	}
+}
+
+func TestClient_Create(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var request domain.CreateAccountBackendRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf(err.Error())
+		}
+		if request.Data.Type != "accounts" || request.Data.ID == "" {
+			t.Errorf("Unexpected request body %s", body)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(domain.CreateAccountBackendResult{Data: request.Data})
+	})
+
+	result, err := client.Create(context.Background(), domain.CreateAccountRequest{
+		OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+		Attributes:     domain.Attributes{Country: "GB", BankID: "400300", Bic: "NWBKGB22", Name: []string{"Fábio"}},
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(result.AccountId) == 0 {
+		t.Errorf("Expected generated account id")
+	}
+}
+
+func TestClient_Delete(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("version") != "3" {
+			t.Errorf("Expected version %s, returned %s", "3", r.URL.Query().Get("version"))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	result, err := client.Delete(context.Background(), accountId, 3)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if !result.Success {
+		t.Errorf("Expected success")
+	}
+}
+
+func TestClient_Update(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		version         int64
+		expected_status int
+		expected_error  error
+	}{
+		{"ShouldSendOnlyChangedFields", 1, http.StatusOK, nil},
+		{"VersionConflict", 0, http.StatusConflict, ErrVersionConflict}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				expected := `{"data":{"attributes":{"name":["Fábio Moraes"]},"id":"` + accountId + `","type":"accounts","version":` + strconv.FormatInt(tc.version, 10) + `}}`
+				if r.Method != http.MethodPatch || string(body) != expected {
+					t.Errorf("Unexpected request %s %s", r.Method, body)
+				}
+
+				w.WriteHeader(tc.expected_status)
+				if tc.expected_status == http.StatusConflict {
+					w.Write([]byte(`{"error_message":"invalid version"}`))
+					return
+				}
+				w.Write([]byte(`{"data":{"id":"` + accountId + `","version":2,"attributes":{"name":["Fábio Moraes"]}}}`))
+			})
+
+			name := []string{"Fábio Moraes"}
+			result, err := client.Update(context.Background(), accountId, tc.version, domain.AttributesPatch{Name: &name})
+			if !errors.Is(err, tc.expected_error) {
+				t.Fatalf("Expected %v, returned %v", tc.expected_error, err)
+			}
+			if err == nil && result.Version != 2 {
+				t.Errorf("Expected version %d, returned %v", 2, result.Version)
+			}
+		})
+	}
+}
+
+func TestClient_CreateWithNationalCheckDigits(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		attributes       domain.Attributes
+		expected_bank_id string
+		expected_account string
+	}{
+		{"ES", domain.Attributes{Country: "ES", BankID: "21000418", Iban: "ES9121000418450200051332"}, "21000418", "0200051332"},
+		{"BE", domain.Attributes{Country: "BE", BankID: "539", Iban: "BE68539007547034"}, "539", "0075470"},
+		{"PT", domain.Attributes{Country: "PT", BankID: "00020123", Iban: "PT50000201231234567890154"}, "00020123", "12345678901"},
+		{"IT", domain.Attributes{Country: "IT", BankID: "0542811101", Iban: "IT60X0542811101000000123456"}, "0542811101", "000000123456"},
+		{"FR", domain.Attributes{Country: "FR", BankID: "2004101005", Iban: "FR1420041010050500013M02606"}, "2004101005", "0500013M026"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sent domain.Attributes
+			client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+				var request domain.CreateAccountBackendRequest
+				json.NewDecoder(r.Body).Decode(&request)
+				sent = request.Data.Attributes
+
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(domain.CreateAccountBackendResult{Data: request.Data})
+			})
+
+			attributes := tc.attributes
+			attributes.Name = []string{"Fábio"}
+			_, err := client.Create(context.Background(), domain.CreateAccountRequest{
+				OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+				Attributes:     attributes,
+			})
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			if sent.BankID != tc.expected_bank_id || sent.AccountNumber != tc.expected_account || sent.Iban != tc.attributes.Iban {
+				t.Errorf("Expected %s %s %s, returned %s %s %s", tc.expected_bank_id, tc.expected_account, tc.attributes.Iban,
+					sent.BankID, sent.AccountNumber, sent.Iban)
+			}
+		})
+	}
+}
+
+func TestClient_CreateShouldNotCallUpstreamWhenInvalid(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	_, err := client.Create(context.Background(), domain.CreateAccountRequest{
+		OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+		Attributes:     domain.Attributes{Name: []string{"Fábio"}},
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected validation error, returned %v", err)
+	}
+	if len(apiErr.FieldErrors) != 1 || apiErr.FieldErrors[0].Field != "country" {
+		t.Errorf("Unexpected field errors %+v", apiErr.FieldErrors)
+	}
+}
+
+func TestClient_CreateWithoutBicReportsOnlyTheBic(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	_, err := client.Create(context.Background(), domain.CreateAccountRequest{
+		OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+		Attributes: domain.Attributes{Country: "GB", BankID: "601613", BankIDCode: "GBDSC", AccountNumber: "31926819",
+			Name: []string{"Fábio"}},
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected validation error, returned %v", err)
+	}
+	if len(apiErr.FieldErrors) != 1 || apiErr.FieldErrors[0].Field != "bic" {
+		t.Errorf("Unexpected field errors %+v", apiErr.FieldErrors)
+	}
+}
+
+func TestClient_CancelAbortsUpstream(t *testing.T) {
+	aborted := make(chan struct{})
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(aborted)
+		case <-time.After(5 * time.Second):
+		}
+	})
+	client = NewClient(WithConfig(client.config), WithTimeout(10*time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.Fetch(ctx, accountId)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, returned %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the fetch to return on cancel, took %s", elapsed)
+	}
+
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the upstream request to be aborted")
+	}
+}
@@ -0,0 +1,36 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/client-library/domain"
+)
+
+// maxDeleteAttempts bounds how often DeleteLatest re-reads the version
+// of an account that keeps changing underneath it.
+const maxDeleteAttempts = 3
+
+// DeleteLatest deletes the account at whatever version it currently has.
+// It fetches the version first and, if the account changes before the delete
+// lands, fetches it again. When the account keeps changing the returned error
+// matches ErrVersionConflict.
+func (c *Client) DeleteLatest(ctx context.Context, accountId string) (*domain.DeleteAccountResult, error) {
+	var err error
+	for attempt := 0; attempt < maxDeleteAttempts; attempt++ {
+		var account *domain.GetAccountByIdResult
+		account, err = c.Fetch(ctx, accountId)
+		if err != nil {
+			return nil, err
+		}
+
+		var result *domain.DeleteAccountResult
+		result, err = c.Delete(ctx, accountId, account.Version)
+		if !errors.Is(err, ErrVersionConflict) {
+			return result, err
+		}
+	}
+
+	return nil, fmt.Errorf("accounts: %s kept changing after %d delete attempts: %w", accountId, maxDeleteAttempts, err)
+}
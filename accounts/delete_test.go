@@ -0,0 +1,81 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// versionedServer serves a single account at version, bumped by every read
+// for the first changes reads, simulating concurrent updates.
+func versionedServer(t *testing.T, version int, changes int) *Client {
+	return stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"data":{"id":"` + accountId + `","version":` + strconv.Itoa(version) + `}}`))
+			if changes > 0 {
+				changes--
+				version++
+			}
+		case http.MethodDelete:
+			if r.URL.Query().Get("version") != strconv.Itoa(version) {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error_message":"invalid version"}`))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+func TestClient_DeleteLatest(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		version        int
+		changes        int
+		expected_error error
+	}{
+		{"ShouldDeleteNewAccount", 0, 0, nil},
+		{"ShouldDeleteModifiedAccount", 3, 0, nil},
+		{"ShouldRetryWhenChangedInBetween", 0, 1, nil},
+		{"KeepsChanging", 0, maxDeleteAttempts, ErrVersionConflict}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := versionedServer(t, tc.version, tc.changes)
+
+			result, err := client.DeleteLatest(context.Background(), accountId)
+			if !errors.Is(err, tc.expected_error) {
+				t.Fatalf("Expected %v, returned %v", tc.expected_error, err)
+			}
+			if err == nil && !result.Success {
+				t.Errorf("Expected success")
+			}
+		})
+	}
+}
+
+func TestClient_DeleteLatest_NotFound(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_message":"record ` + accountId + ` does not exist"}`))
+	})
+
+	if _, err := client.DeleteLatest(context.Background(), accountId); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %v, returned %v", ErrNotFound, err)
+	}
+}
+
+func TestClient_DeleteLatest_InvalidAccountId(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	for _, id := range []string{"", "not-a-uuid"} {
+		if _, err := client.DeleteLatest(context.Background(), id); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected %v, returned %v", ErrValidation, err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/client-library/domain"
+)
+
+// Sentinel errors matched with errors.Is against the errors returned by the Client.
+var (
+	ErrNotFound        = errors.New("account not found")
+	ErrDuplicate       = errors.New("account violates a duplicate constraint")
+	ErrVersionConflict = errors.New("account version conflict")
+	ErrValidation      = errors.New("account validation failed")
+	ErrUnavailable     = errors.New("account api unavailable")
+
+	// ErrTimeout and ErrConnection are matched by a TransportError, together
+	// with ErrUnavailable.
+	ErrTimeout    = errors.New("account api timed out")
+	ErrConnection = errors.New("account api connection failed")
+)
+
+// APIError is returned when the account API answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Operation  Operation
+	AccountID  string
+
+	// FieldErrors lists the attributes rejected by a 400 response.
+	FieldErrors []domain.FieldError
+}
+
+func (e *APIError) Error() string {
+	operation := string(e.Operation)
+	if len(e.AccountID) > 0 {
+		operation += " " + e.AccountID
+	}
+	return fmt.Sprintf("accounts: %s: %d %s", operation, e.StatusCode, e.Message)
+}
+
+// Unwrap exposes the sentinel error matching the status, so callers can use errors.Is.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict && strings.Contains(e.Message, "duplicate constraint"):
+		return ErrDuplicate
+	case e.StatusCode == http.StatusConflict:
+		return ErrVersionConflict
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrValidation
+	case e.StatusCode == http.StatusBadGateway,
+		e.StatusCode == http.StatusServiceUnavailable,
+		e.StatusCode == http.StatusGatewayTimeout:
+		return ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// newAPIError reads the error_message envelope of a failed response,
+// falling back to the raw body or the status text.
+func newAPIError(operation Operation, accountId string, statusCode int, body []byte) *APIError {
+	var exc domain.CustomException
+	message := ""
+	if err := json.Unmarshal(body, &exc); err == nil {
+		message = exc.ErrorMessage
+	}
+	if len(message) == 0 {
+		message = strings.TrimSpace(string(body))
+	}
+	if len(message) == 0 {
+		message = http.StatusText(statusCode)
+	}
+
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Message:    message,
+		Operation:  operation,
+		AccountID:  accountId,
+	}
+	if statusCode == http.StatusBadRequest {
+		apiErr.FieldErrors = ParseFieldErrors(message)
+	}
+
+	return apiErr
+}
+
+// TransportError is returned when the account API gave no response: the
+// connection failed or the operation timed out.
+type TransportError struct {
+	Operation Operation
+	AccountID string
+	Timeout   bool
+	Err       error
+}
+
+func (e *TransportError) Error() string {
+	operation := string(e.Operation)
+	if len(e.AccountID) > 0 {
+		operation += " " + e.AccountID
+	}
+	kind := "connection failed"
+	if e.Timeout {
+		kind = "timed out"
+	}
+	return fmt.Sprintf("accounts: %s: %s: %v", operation, kind, e.Err)
+}
+
+// Unwrap exposes the cause, e.g. context.DeadlineExceeded or a *net.OpError.
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrUnavailable, and ErrTimeout or ErrConnection by the kind of failure.
+func (e *TransportError) Is(target error) bool {
+	switch target {
+	case ErrUnavailable:
+		return true
+	case ErrTimeout:
+		return e.Timeout
+	case ErrConnection:
+		return !e.Timeout
+	default:
+		return false
+	}
+}
+
+// StatusCode is the status a gateway answers for the failure: 504 for a
+// timeout and 502 for any other connection error.
+func (e *TransportError) StatusCode() int {
+	if e.Timeout {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+// newTransportError classifies an error of the http.Client. A cancellation by
+// the caller is returned unchanged.
+func newTransportError(operation Operation, accountId string, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	var netErr net.Error
+	timeout := errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+
+	return &TransportError{
+		Operation: operation,
+		AccountID: accountId,
+		Timeout:   timeout,
+		Err:       err,
+	}
+}
@@ -0,0 +1,114 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testCasesAPIError = []struct {
+	name             string
+	status_code      int
+	body             string
+	expected_error   error
+	expected_message string
+}{
+	{"NotFound", http.StatusNotFound, `{"error_message":"record ` + accountId + ` does not exist"}`, ErrNotFound, "record " + accountId + " does not exist"},
+	{"Duplicate", http.StatusConflict, `{"error_message":"Account cannot be created as it violates a duplicate constraint"}`, ErrDuplicate, "Account cannot be created as it violates a duplicate constraint"},
+	{"VersionConflict", http.StatusConflict, `{"error_message":"invalid version"}`, ErrVersionConflict, "invalid version"},
+	{"Validation", http.StatusBadRequest, `{"error_message":"validation failure list:\ncountry in body is required"}`, ErrValidation, "validation failure list:\ncountry in body is required"},
+	{"Unavailable", http.StatusServiceUnavailable, ``, ErrUnavailable, "Service Unavailable"},
+	{"PlainTextBody", http.StatusBadGateway, "upstream down\n", ErrUnavailable, "upstream down"}}
+
+func TestClient_APIError(t *testing.T) {
+	for _, tc := range testCasesAPIError {
+		t.Run(tc.name, func(t *testing.T) {
+			client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status_code)
+				w.Write([]byte(tc.body))
+			})
+
+			_, err := client.Fetch(context.Background(), accountId)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected APIError, returned %v", err)
+			}
+
+			if !errors.Is(err, tc.expected_error) {
+				t.Errorf("Expected %v, returned %v", tc.expected_error, err)
+			}
+			if apiErr.StatusCode != tc.status_code {
+				t.Errorf("Expected %d, returned %d", tc.status_code, apiErr.StatusCode)
+			}
+			if apiErr.Message != tc.expected_message {
+				t.Errorf("Expected %s, returned %s", tc.expected_message, apiErr.Message)
+			}
+			if apiErr.Operation != OperationFetch || apiErr.AccountID != accountId {
+				t.Errorf("Unexpected operation %s and account %s", apiErr.Operation, apiErr.AccountID)
+			}
+		})
+	}
+}
+
+func TestClient_TransportError(t *testing.T) {
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	t.Cleanup(slow.Close)
+
+	var testCases = []struct {
+		name                 string
+		url                  string
+		expected_error       error
+		expected_status_code int
+	}{
+		{"ConnectionRefused", refused.URL, ErrConnection, http.StatusBadGateway},
+		{"SlowServer", slow.URL, ErrTimeout, http.StatusGatewayTimeout}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(WithBaseURL(tc.url), WithTimeout(10*time.Millisecond), WithRetryPolicy(NoRetry))
+
+			_, err := client.Fetch(context.Background(), accountId)
+
+			var transportErr *TransportError
+			if !errors.As(err, &transportErr) {
+				t.Fatalf("Expected TransportError, returned %v", err)
+			}
+
+			if !errors.Is(err, tc.expected_error) || !errors.Is(err, ErrUnavailable) {
+				t.Errorf("Expected %v and %v, returned %v", tc.expected_error, ErrUnavailable, err)
+			}
+			if transportErr.StatusCode() != tc.expected_status_code {
+				t.Errorf("Expected %d, returned %d", tc.expected_status_code, transportErr.StatusCode())
+			}
+			if transportErr.Operation != OperationFetch || transportErr.AccountID != accountId {
+				t.Errorf("Unexpected operation %s and account %s", transportErr.Operation, transportErr.AccountID)
+			}
+		})
+	}
+}
+
+func TestClient_TransportErrorKeepsCancellation(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Fetch(ctx, accountId)
+
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, returned %v", err)
+	}
+}
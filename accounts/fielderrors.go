@@ -0,0 +1,63 @@
+package accounts
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/client-library/domain"
+)
+
+var fieldErrorPatterns = []struct {
+	rule    string
+	pattern *regexp.Regexp
+}{
+	{domain.RuleRequired, regexp.MustCompile(`^(\S+) in \w+ is required$`)},
+	{domain.RuleType, regexp.MustCompile(`^(\S+) in \w+ must be of type (\w+): "(.*)"$`)},
+	{domain.RulePattern, regexp.MustCompile(`^(\S+) in \w+ should match '(.*)'$`)},
+	{domain.RuleMaxLength, regexp.MustCompile(`^(\S+) in \w+ should be at most (\d+) chars long$`)},
+	{domain.RuleMinLength, regexp.MustCompile(`^(\S+) in \w+ should be at least (\d+) chars long$`)},
+	{domain.RuleEnum, regexp.MustCompile(`^(\S+) in \w+ should be one of \[(.*)\]$`)},
+	{domain.RuleMaxItems, regexp.MustCompile(`^(\S+) in \w+ should have at most (\d+) items$`)},
+	{domain.RuleMinItems, regexp.MustCompile(`^(\S+) in \w+ should have at least (\d+) items$`)},
+	{domain.RuleInvalid, regexp.MustCompile(`^(\S+) in \w+ `)},
+}
+
+// ParseFieldErrors turns the free text validation failures of the account API,
+// e.g. "validation failure list:\ncountry in body is required", into FieldErrors.
+// Lines that do not name a field are ignored.
+func ParseFieldErrors(message string) []domain.FieldError {
+	var fieldErrors []domain.FieldError
+
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "validation failure list") {
+			continue
+		}
+
+		for _, p := range fieldErrorPatterns {
+			match := p.pattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+
+			fieldError := domain.FieldError{Field: normaliseField(match[1]), Rule: p.rule, Message: line}
+			if len(match) > 2 {
+				fieldError.Param = match[2]
+			}
+			if len(match) > 3 {
+				fieldError.Value = match[3]
+			}
+			fieldErrors = append(fieldErrors, fieldError)
+			break
+		}
+	}
+
+	return fieldErrors
+}
+
+// normaliseField drops the JSON:API envelope prefix, so "data.attributes.country"
+// and "country" both report as "country". Array indexes such as "name.0" are kept.
+func normaliseField(field string) string {
+	field = strings.TrimPrefix(field, "data.")
+	return strings.TrimPrefix(field, "attributes.")
+}
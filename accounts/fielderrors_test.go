@@ -0,0 +1,46 @@
+package accounts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/client-library/domain"
+)
+
+var testCasesFieldErrors = []struct {
+	name     string
+	message  string
+	expected []domain.FieldError
+}{
+	{"Required", "validation failure list:\nvalidation failure list:\ncountry in body is required\nname in body is required",
+		[]domain.FieldError{
+			{Field: "country", Rule: domain.RuleRequired, Message: "country in body is required"},
+			{Field: "name", Rule: domain.RuleRequired, Message: "name in body is required"}}},
+	{"InvalidUUID", "validation failure list:\norganisation_id in body must be of type uuid: \"0d077184-ca1b-4583-a416-29c9a51cf6e\"",
+		[]domain.FieldError{
+			{Field: "organisation_id", Rule: domain.RuleType, Param: "uuid", Value: "0d077184-ca1b-4583-a416-29c9a51cf6e",
+				Message: "organisation_id in body must be of type uuid: \"0d077184-ca1b-4583-a416-29c9a51cf6e\""}}},
+	{"Pattern", "validation failure list:\nvalidation failure list:\ncountry in body should match '^[A-Z]{2}$'",
+		[]domain.FieldError{
+			{Field: "country", Rule: domain.RulePattern, Param: "^[A-Z]{2}$", Message: "country in body should match '^[A-Z]{2}$'"}}},
+	{"MaxLength", "validation failure list:\nvalidation failure list:\nname.0 in body should be at most 140 chars long",
+		[]domain.FieldError{
+			{Field: "name.0", Rule: domain.RuleMaxLength, Param: "140", Message: "name.0 in body should be at most 140 chars long"}}},
+	{"Enum", "validation failure list:\ntype in body should be one of [accounts]",
+		[]domain.FieldError{
+			{Field: "type", Rule: domain.RuleEnum, Param: "accounts", Message: "type in body should be one of [accounts]"}}},
+	{"EnvelopePrefix", "data.attributes.bank_id_code in body should have at most 4 items",
+		[]domain.FieldError{
+			{Field: "bank_id_code", Rule: domain.RuleMaxItems, Param: "4", Message: "data.attributes.bank_id_code in body should have at most 4 items"}}},
+	{"NotAFieldError", "Account cannot be created as it violates a duplicate constraint", nil}}
+
+func TestParseFieldErrors(t *testing.T) {
+	for _, tc := range testCasesFieldErrors {
+		t.Run(tc.name, func(t *testing.T) {
+			fieldErrors := ParseFieldErrors(tc.message)
+			if !reflect.DeepEqual(fieldErrors, tc.expected) {
+				t.Errorf("Expected %+v, returned %+v", tc.expected, fieldErrors)
+			}
+		})
+	}
+}
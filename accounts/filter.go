@@ -0,0 +1,93 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/client-library/domain"
+)
+
+// AccountFilter narrows a List down on the server with filter[...] query parameters.
+// Fields holding several values match any of them.
+type AccountFilter struct {
+	OrganisationID []string
+	AccountNumber  []string
+	BankID         []string
+	BankIDCode     []string
+	Country        []string
+	CustomerID     []string
+	Iban           []string
+}
+
+// filterParameters maps the filter[...] names to the AccountFilter fields.
+func (f *AccountFilter) filterParameters() []struct {
+	name   string
+	values *[]string
+} {
+	return []struct {
+		name   string
+		values *[]string
+	}{
+		{"organisation_id", &f.OrganisationID},
+		{"account_number", &f.AccountNumber},
+		{"bank_id", &f.BankID},
+		{"bank_id_code", &f.BankIDCode},
+		{"country", &f.Country},
+		{"customer_id", &f.CustomerID},
+		{"iban", &f.Iban},
+	}
+}
+
+// encode adds the filter to query, joining multiple values with commas,
+// e.g. filter[country]=GB,FR.
+func (f AccountFilter) encode(query url.Values) {
+	for _, p := range f.filterParameters() {
+		if len(*p.values) > 0 {
+			query.Set("filter["+p.name+"]", strings.Join(*p.values, ","))
+		}
+	}
+}
+
+// FilterFromQuery reads the filter[...] parameters of an incoming request.
+func FilterFromQuery(query url.Values) AccountFilter {
+	var filter AccountFilter
+	for _, p := range filter.filterParameters() {
+		for _, value := range query["filter["+p.name+"]"] {
+			for _, v := range strings.Split(value, ",") {
+				if v = strings.TrimSpace(v); len(v) > 0 {
+					*p.values = append(*p.values, v)
+				}
+			}
+		}
+	}
+	return filter
+}
+
+// FindByIBAN returns the account holding the iban, or ErrNotFound.
+func (c *Client) FindByIBAN(ctx context.Context, iban string) (*domain.AccountResult, error) {
+	return c.findOne(ctx, AccountFilter{Iban: []string{iban}}, "iban "+iban)
+}
+
+// FindByAccountNumber returns the account identified by bank ID and account number, or ErrNotFound.
+func (c *Client) FindByAccountNumber(ctx context.Context, bankID string, accountNumber string) (*domain.AccountResult, error) {
+	filter := AccountFilter{BankID: []string{bankID}, AccountNumber: []string{accountNumber}}
+	return c.findOne(ctx, filter, "bank_id "+bankID+" account_number "+accountNumber)
+}
+
+func (c *Client) findOne(ctx context.Context, filter AccountFilter, description string) (*domain.AccountResult, error) {
+	result, err := c.List(ctx, ListOptions{PageSize: 2, Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(result.Accounts) {
+	case 0:
+		return nil, fmt.Errorf("accounts: no account with %s: %w", description, ErrNotFound)
+	case 1:
+		return &result.Accounts[0], nil
+	default:
+		return nil, fmt.Errorf("accounts: more than one account with %s", description)
+	}
+}
@@ -0,0 +1,76 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/client-library/domain"
+)
+
+func TestAccountFilter_Encode(t *testing.T) {
+	query := ListOptions{PageSize: 10, Filter: AccountFilter{
+		Country: []string{"GB", "FR"},
+		BankID:  []string{"400300"}}}.query()
+
+	if query.Get("filter[country]") != "GB,FR" || query.Get("filter[bank_id]") != "400300" {
+		t.Errorf("Unexpected query %s", query.Encode())
+	}
+	if query.Has("filter[iban]") {
+		t.Errorf("Expected empty filters to be omitted, returned %s", query.Encode())
+	}
+
+	filter := FilterFromQuery(query)
+	expected := AccountFilter{Country: []string{"GB", "FR"}, BankID: []string{"400300"}}
+	if !reflect.DeepEqual(filter, expected) {
+		t.Errorf("Expected %+v, returned %+v", expected, filter)
+	}
+}
+
+func TestFilterFromQuery_RepeatedParameters(t *testing.T) {
+	query, _ := url.ParseQuery("filter[iban]=GB33BUKB20201555555555&filter[iban]=GB94BARC10201530093459,&page[size]=1")
+
+	filter := FilterFromQuery(query)
+	expected := []string{"GB33BUKB20201555555555", "GB94BARC10201530093459"}
+	if !reflect.DeepEqual(filter.Iban, expected) {
+		t.Errorf("Expected %v, returned %v", expected, filter.Iban)
+	}
+}
+
+func TestClient_FindByAccountNumber(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		matches        int
+		expected_error error
+	}{
+		{"Found", 1, nil},
+		{"NotFound", 0, ErrNotFound}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("filter[bank_id]") != "400300" || r.URL.Query().Get("filter[account_number]") != "41426819" {
+					t.Errorf("Unexpected query %s", r.URL.RawQuery)
+				}
+
+				var result domain.ListAccountsBackendResult
+				for i := 0; i < tc.matches; i++ {
+					result.Data = append(result.Data, domain.Data{ID: accountId})
+				}
+				json.NewEncoder(w).Encode(result)
+			})
+
+			account, err := client.FindByAccountNumber(context.Background(), "400300", "41426819")
+			if !errors.Is(err, tc.expected_error) {
+				t.Fatalf("Expected %v, returned %v", tc.expected_error, err)
+			}
+			if err == nil && account.AccountId != accountId {
+				t.Errorf("Expected %s, returned %s", accountId, account.AccountId)
+			}
+		})
+	}
+}
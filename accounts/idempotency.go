@@ -0,0 +1,74 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/client-library/domain"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyNamespace is the UUIDv5 namespace of the account IDs derived from
+// idempotency keys.
+var IdempotencyNamespace = uuid.MustParse("6f1c2a52-8d1e-5b7a-9c43-2f0e4d6a8b19")
+
+// IDFromIdempotencyKey maps an idempotency key to a deterministic account ID,
+// so every retry of a create carrying the key targets the same account.
+func IDFromIdempotencyKey(organisationId string, key string) string {
+	return uuid.NewSHA1(IdempotencyNamespace, []byte(organisationId+"/"+key)).String()
+}
+
+// existing resolves a duplicate constraint error on a create with a caller
+// supplied ID: when the stored account matches the request it is returned as
+// if it had just been created, otherwise the duplicate error stands.
+func (c *Client) existing(ctx context.Context, request domain.CreateAccountRequest, duplicate error) (*domain.CreateAccountResult, error) {
+	stored, err := c.fetch(ctx, request.ID)
+	if err != nil {
+		return nil, duplicate
+	}
+
+	if stored.Data.OrganisationID != request.OrganisationID || !attributesMatch(request.Attributes, stored.Data.Attributes) {
+		return nil, duplicate
+	}
+
+	var result domain.CreateAccountResult
+	result.AccountId = stored.Data.ID
+	result.Attributes = stored.Data.Attributes
+	result.CreatedOn = stored.Data.CreatedOn
+
+	return &result, nil
+}
+
+// attributesMatch reports whether every attribute set in requested has the same
+// value in stored. Attributes the account API fills in itself, such as a
+// generated IBAN, are ignored.
+func attributesMatch(requested domain.Attributes, stored domain.Attributes) bool {
+	requestedFields, err := toFields(requested)
+	if err != nil {
+		return false
+	}
+	storedFields, err := toFields(stored)
+	if err != nil {
+		return false
+	}
+
+	for field, value := range requestedFields {
+		if !reflect.DeepEqual(value, storedFields[field]) {
+			return false
+		}
+	}
+	return true
+}
+
+func toFields(attributes domain.Attributes) (map[string]interface{}, error) {
+	attributesJson, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	err = json.Unmarshal(attributesJson, &fields)
+	return fields, err
+}
@@ -0,0 +1,82 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/client-library/domain"
+)
+
+var idempotentRequest = domain.CreateAccountRequest{
+	ID:             accountId,
+	OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+	Attributes:     domain.Attributes{Country: "GB", BankID: "400300", Bic: "NWBKGB22", Name: []string{"Fábio"}},
+}
+
+// storedAccountServer rejects every create as a duplicate of the stored account.
+func storedAccountServer(t *testing.T, stored domain.Data) *Client {
+	return stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			body, _ := ioutil.ReadAll(r.Body)
+			var request domain.CreateAccountBackendRequest
+			json.Unmarshal(body, &request)
+			if request.Data.ID != accountId {
+				t.Errorf("Expected caller supplied id, returned %s", request.Data.ID)
+			}
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error_message":"Account cannot be created as it violates a duplicate constraint"}`))
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(domain.GetAccountByIdBackendResult{Data: stored})
+		}
+	})
+}
+
+func TestClient_CreateIsIdempotent(t *testing.T) {
+	stored := domain.Data{ID: accountId, OrganisationID: idempotentRequest.OrganisationID, Attributes: idempotentRequest.Attributes}
+	stored.Attributes.BankIDCode = "GBDSC"
+	stored.Attributes.Iban = "GB11NWBK40030041426819"
+
+	var testCases = []struct {
+		name           string
+		change         func(d *domain.Data)
+		expected_error error
+	}{
+		{"SameAttributesReturnsStoredAccount", func(d *domain.Data) {}, nil},
+		{"DifferentAttributesIsDuplicate", func(d *domain.Data) { d.Attributes.Name = []string{"Someone else"} }, ErrDuplicate},
+		{"DifferentOrganisationIsDuplicate", func(d *domain.Data) { d.OrganisationID = "cc3a78fe-1785-11ed-861d-0242ac120002" }, ErrDuplicate}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := stored
+			tc.change(&data)
+			client := storedAccountServer(t, data)
+
+			result, err := client.Create(context.Background(), idempotentRequest)
+			if !errors.Is(err, tc.expected_error) {
+				t.Fatalf("Expected %v, returned %v", tc.expected_error, err)
+			}
+			if err == nil && result.AccountId != accountId {
+				t.Errorf("Expected %s, returned %s", accountId, result.AccountId)
+			}
+		})
+	}
+}
+
+func TestIDFromIdempotencyKey(t *testing.T) {
+	first := IDFromIdempotencyKey(idempotentRequest.OrganisationID, "order-42")
+
+	if first != IDFromIdempotencyKey(idempotentRequest.OrganisationID, "order-42") {
+		t.Errorf("Expected the same id for the same key")
+	}
+	if first == IDFromIdempotencyKey(idempotentRequest.OrganisationID, "order-43") {
+		t.Errorf("Expected different ids for different keys")
+	}
+	if len(domain.CreateAccountRequest{ID: first, OrganisationID: idempotentRequest.OrganisationID, Attributes: idempotentRequest.Attributes}.Validate()) > 0 {
+		t.Errorf("Expected %s to be a valid account id", first)
+	}
+}
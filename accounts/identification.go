@@ -0,0 +1,75 @@
+package accounts
+
+import (
+	"errors"
+
+	"github.com/client-library/domain"
+	"github.com/client-library/iban"
+)
+
+// reconcileIban keeps iban, bank_id and account_number of a create consistent:
+// it fills bank_id and account_number from a supplied IBAN, builds the IBAN
+// when only the national identification is given, and reports the attributes
+// that contradict each other. Countries without a known IBAN layout are left
+// to the account API.
+func reconcileIban(attributes domain.Attributes) (domain.Attributes, []domain.FieldError) {
+	if len(attributes.Iban) == 0 {
+		if len(attributes.BankID) == 0 || len(attributes.AccountNumber) == 0 {
+			return attributes, nil
+		}
+		generated, err := iban.Build(components(attributes))
+		// a missing bic is reported by the country rules
+		if errors.Is(err, iban.ErrCountry) || errors.Is(err, iban.ErrBankCode) {
+			return attributes, nil
+		}
+		if err != nil {
+			return attributes, []domain.FieldError{{Field: "iban", Rule: domain.RuleInvalid,
+				Message: "iban cannot be built from bank_id and account_number: " + err.Error()}}
+		}
+		attributes.Iban = generated
+		return attributes, nil
+	}
+
+	attributes.Iban = iban.Normalise(attributes.Iban)
+	parsed, err := iban.Parse(attributes.Iban)
+	if errors.Is(err, iban.ErrCountry) {
+		return attributes, nil
+	}
+	if err != nil {
+		return attributes, []domain.FieldError{{Field: "iban", Rule: domain.RuleInvalid, Value: attributes.Iban,
+			Message: "iban in body is not valid: " + err.Error()}}
+	}
+
+	if len(attributes.Country) > 0 && parsed.Country != attributes.Country {
+		return attributes, []domain.FieldError{{Field: "iban", Rule: domain.RuleInvalid, Value: attributes.Iban,
+			Message: "iban in body does not belong to country " + attributes.Country}}
+	}
+
+	if len(attributes.BankID) == 0 {
+		attributes.BankID = parsed.BankID
+	}
+	if len(attributes.AccountNumber) == 0 {
+		attributes.AccountNumber = parsed.AccountNumber
+	}
+
+	expected := components(attributes)
+	if len(expected.BankCode) == 0 {
+		expected.BankCode = parsed.BankCode
+	}
+	expected.NationalCheck = parsed.NationalCheck
+	if built, err := iban.Build(expected); err != nil || built != attributes.Iban {
+		return attributes, []domain.FieldError{{Field: "iban", Rule: domain.RuleInvalid, Value: attributes.Iban,
+			Message: "iban in body does not match bank_id and account_number"}}
+	}
+
+	return attributes, nil
+}
+
+func components(attributes domain.Attributes) iban.Components {
+	return iban.Components{
+		Country:       attributes.Country,
+		BankCode:      attributes.Bic,
+		BankID:        attributes.BankID,
+		AccountNumber: attributes.AccountNumber,
+	}
+}
@@ -0,0 +1,49 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/client-library/domain"
+)
+
+func TestReconcileIban(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		attributes       domain.Attributes
+		expected_iban    string
+		expected_account string
+		expected_error   bool
+	}{
+		{"ShouldBuildIban", domain.Attributes{Country: "GB", Bic: "NWBKGB22", BankID: "601613", AccountNumber: "31926819"}, "GB29NWBK60161331926819", "31926819", false},
+		{"ShouldFillAccountNumber", domain.Attributes{Country: "GB", BankID: "601613", Iban: "GB29 NWBK 6016 1331 9268 19"}, "GB29NWBK60161331926819", "31926819", false},
+		{"ShouldAcceptConsistentAttributes", domain.Attributes{Country: "DE", BankID: "37040044", AccountNumber: "532013000", Iban: "DE89370400440532013000"}, "DE89370400440532013000", "532013000", false},
+		{"AccountNumberDoesNotMatch", domain.Attributes{Country: "GB", BankID: "601613", AccountNumber: "31926818", Iban: "GB29NWBK60161331926819"}, "", "", true},
+		{"WrongCountry", domain.Attributes{Country: "FR", Iban: "GB29NWBK60161331926819"}, "", "", true},
+		{"InvalidCheckDigits", domain.Attributes{Country: "GB", Iban: "GB28NWBK60161331926819"}, "", "", true},
+		{"NoBicIsLeftToTheCountryRules", domain.Attributes{Country: "GB", BankID: "601613", AccountNumber: "31926819"}, "", "31926819", false},
+		{"NoLayoutIsLeftToTheAPI", domain.Attributes{Country: "US", BankID: "021000021", AccountNumber: "123456"}, "", "123456", false},
+		{"ShouldSplitSpanishCheckDigits", domain.Attributes{Country: "ES", Iban: "ES9121000418450200051332"}, "ES9121000418450200051332", "0200051332", false},
+		{"ShouldBuildSpanishCheckDigits", domain.Attributes{Country: "ES", BankID: "21000418", AccountNumber: "0200051332"}, "ES9121000418450200051332", "0200051332", false},
+		{"ShouldAcceptItalianCIN", domain.Attributes{Country: "IT", BankID: "0542811101", AccountNumber: "000000123456", Iban: "IT60X0542811101000000123456"}, "IT60X0542811101000000123456", "000000123456", false},
+		{"CannotBuildFromLayout", domain.Attributes{Country: "ES", BankID: "21000418", AccountNumber: "450200051332"}, "", "", true}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attributes, fieldErrors := reconcileIban(tc.attributes)
+
+			if tc.expected_error {
+				if len(fieldErrors) != 1 || fieldErrors[0].Field != "iban" {
+					t.Errorf("Expected iban error, returned %+v", fieldErrors)
+				}
+				return
+			}
+
+			if len(fieldErrors) > 0 {
+				t.Fatalf("Expected no errors, returned %+v", fieldErrors)
+			}
+			if attributes.Iban != tc.expected_iban || attributes.AccountNumber != tc.expected_account {
+				t.Errorf("Expected %s %s, returned %s %s", tc.expected_iban, tc.expected_account, attributes.Iban, attributes.AccountNumber)
+			}
+		})
+	}
+}
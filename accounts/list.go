@@ -0,0 +1,149 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/client-library/domain"
+)
+
+// ListOptions selects a page of accounts. Zero values leave the choice to the API,
+// which starts at page 0 with its default page size.
+type ListOptions struct {
+	PageNumber int
+	PageSize   int
+	Filter     AccountFilter
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	if o.PageNumber > 0 {
+		query.Set("page[number]", strconv.Itoa(o.PageNumber))
+	}
+	if o.PageSize > 0 {
+		query.Set("page[size]", strconv.Itoa(o.PageSize))
+	}
+	o.Filter.encode(query)
+	return query
+}
+
+// List returns a single page of accounts together with the pagination links.
+func (c *Client) List(ctx context.Context, opts ListOptions) (*domain.ListAccountsResult, error) {
+	url := c.baseURL
+	if query := opts.query().Encode(); len(query) > 0 {
+		url += "?" + query
+	}
+
+	return c.list(ctx, url)
+}
+
+func (c *Client) list(ctx context.Context, url string) (*domain.ListAccountsResult, error) {
+	body, err := c.do(ctx, OperationList, "", http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var backendResult domain.ListAccountsBackendResult
+	if err := json.Unmarshal(body, &backendResult); err != nil {
+		return nil, err
+	}
+
+	var result domain.ListAccountsResult
+	result.Links = backendResult.Links
+	result.Accounts = make([]domain.AccountResult, 0, len(backendResult.Data))
+	for _, data := range backendResult.Data {
+		result.Accounts = append(result.Accounts, toAccountResult(data))
+	}
+
+	return &result, nil
+}
+
+func toAccountResult(data domain.Data) domain.AccountResult {
+	return domain.AccountResult{
+		AccountId:      data.ID,
+		OrganisationID: data.OrganisationID,
+		Version:        data.Version,
+		CreatedOn:      data.CreatedOn,
+		ModifiedOn:     data.ModifiedOn,
+		Attributes:     data.Attributes,
+	}
+}
+
+// resolve turns a pagination link, which the API returns relative to its host,
+// into an absolute URL.
+func (c *Client) resolve(link string) (string, error) {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// Iterator walks every account, following the next links page by page.
+//
+//	it := client.Iterate(ctx, accounts.ListOptions{PageSize: 100})
+//	for it.Next() {
+//		account := it.Account()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator struct {
+	ctx     context.Context
+	client  *Client
+	nextURL string
+	page    []domain.AccountResult
+	current domain.AccountResult
+	err     error
+}
+
+// Iterate returns an Iterator starting at the page selected by opts.
+func (c *Client) Iterate(ctx context.Context, opts ListOptions) *Iterator {
+	url := c.baseURL
+	if query := opts.query().Encode(); len(query) > 0 {
+		url += "?" + query
+	}
+
+	return &Iterator{ctx: ctx, client: c, nextURL: url}
+}
+
+// Next advances to the next account, fetching the next page when needed.
+// It returns false when every page has been read or an error occurred.
+func (it *Iterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || len(it.nextURL) == 0 {
+			return false
+		}
+
+		result, err := it.client.list(it.ctx, it.nextURL)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = result.Accounts
+		it.nextURL = ""
+		if len(result.Links.Next) > 0 && len(result.Accounts) > 0 {
+			it.nextURL, it.err = it.client.resolve(result.Links.Next)
+		}
+	}
+
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Account returns the account read by the last call to Next.
+func (it *Iterator) Account() domain.AccountResult {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,87 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/client-library/domain"
+)
+
+// pagedServer serves total accounts in pages, with next links relative to the host
+// the way the account API does.
+func pagedServer(t *testing.T, total int) *Client {
+	return stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		pageNumber, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+		if pageSize == 0 {
+			pageSize = 100
+		}
+
+		var result domain.ListAccountsBackendResult
+		for i := pageNumber * pageSize; i < total && i < (pageNumber+1)*pageSize; i++ {
+			result.Data = append(result.Data, domain.Data{ID: fmt.Sprintf("account-%d", i)})
+		}
+		result.Links.Self = fmt.Sprintf("/v1/organisation/accounts?page%%5Bnumber%%5D=%d&page%%5Bsize%%5D=%d", pageNumber, pageSize)
+		if (pageNumber+1)*pageSize < total {
+			result.Links.Next = fmt.Sprintf("/v1/organisation/accounts?page%%5Bnumber%%5D=%d&page%%5Bsize%%5D=%d", pageNumber+1, pageSize)
+		}
+
+		json.NewEncoder(w).Encode(result)
+	})
+}
+
+func TestClient_List(t *testing.T) {
+	client := pagedServer(t, 5)
+
+	result, err := client.List(context.Background(), ListOptions{PageNumber: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if len(result.Accounts) != 2 || result.Accounts[0].AccountId != "account-2" {
+		t.Errorf("Unexpected page %+v", result.Accounts)
+	}
+	if len(result.Links.Next) == 0 {
+		t.Errorf("Expected next link")
+	}
+}
+
+func TestClient_Iterate(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		total     int
+		page_size int
+	}{
+		{"NoAccounts", 0, 2},
+		{"SinglePage", 2, 5},
+		{"ExactPages", 4, 2},
+		{"PartialLastPage", 5, 2}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := pagedServer(t, tc.total)
+
+			var ids []string
+			it := client.Iterate(context.Background(), ListOptions{PageSize: tc.page_size})
+			for it.Next() {
+				ids = append(ids, it.Account().AccountId)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			if len(ids) != tc.total {
+				t.Fatalf("Expected %d, returned %d", tc.total, len(ids))
+			}
+			for i, id := range ids {
+				if id != fmt.Sprintf("account-%d", i) {
+					t.Errorf("Expected account-%d, returned %s", i, id)
+				}
+			}
+		})
+	}
+}
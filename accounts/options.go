@@ -0,0 +1,291 @@
+package accounts
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/client-library/bankdir"
+	"github.com/client-library/countryrules"
+	"github.com/client-library/modulus"
+)
+
+// Operation names a call made by the Client, used to configure it per operation.
+type Operation string
+
+const (
+	OperationFetch    Operation = "fetch"
+	OperationCreate   Operation = "create"
+	OperationDelete   Operation = "delete"
+	OperationList     Operation = "list"
+	OperationUpdate   Operation = "update"
+	OperationValidate Operation = "validate"
+	OperationBank     Operation = "bank"
+)
+
+const (
+	DefaultBaseURL    = "http://localhost:8080"
+	DefaultAPIVersion = "v1"
+	DefaultTimeout    = time.Duration(1) * time.Second
+	DefaultUserAgent  = "form3-client-library"
+)
+
+// Config holds everything the Client needs to reach the account API.
+type Config struct {
+	BaseURL           string
+	APIVersion        string
+	Timeout           time.Duration
+	OperationTimeouts map[Operation]time.Duration
+	UserAgent         string
+	Transport         http.RoundTripper
+
+	// RetryPolicy applies to the idempotent operations and to creates with a
+	// caller supplied ID; OperationRetryPolicies overrides it per operation.
+	RetryPolicy            RetryPolicy
+	OperationRetryPolicies map[Operation]RetryPolicy
+
+	// Metrics, when set, is told about every retry.
+	Metrics Metrics
+
+	// Breaker fails fast with ErrCircuitOpen while the account API keeps
+	// failing; nil turns it off.
+	Breaker *CircuitBreaker
+
+	// CountryRules normalises and validates creates per country; nil disables it.
+	CountryRules *countryrules.Registry
+
+	// ModulusChecker checks UK sort codes and account numbers offline. When
+	// ModulusCheckOnCreate is set, GB creates with GBDSC must pass it too.
+	ModulusChecker       *modulus.Checker
+	ModulusCheckOnCreate bool
+
+	// BankDirectory, when set, enriches fetches with the bank name and branch
+	// and rejects creates whose BIC is unknown or inconsistent with bank_id.
+	BankDirectory *bankdir.Directory
+}
+
+// Option changes the Config used by NewClient.
+type Option func(*Config)
+
+// DefaultConfig returns the built-in defaults overridden by the environment:
+//
+//	ACCOUNT_API_BASE_URL       e.g. http://accountapi:8080
+//	ACCOUNT_API_VERSION        e.g. v1
+//	ACCOUNT_API_TIMEOUT        e.g. 2s, used by every operation
+//	ACCOUNT_API_<OP>_TIMEOUT   e.g. ACCOUNT_API_CREATE_TIMEOUT=5s
+//	ACCOUNT_API_USER_AGENT
+//	ACCOUNT_API_RETRY_MAX_ATTEMPTS  e.g. 1 to disable retries
+//	ACCOUNT_API_BREAKER_THRESHOLD   consecutive failed calls opening the breaker, 0 disables it
+//	ACCOUNT_API_BREAKER_COOLDOWN    e.g. 30s before a probe is let through
+//
+// Durations that cannot be parsed are ignored and the default is kept.
+func DefaultConfig() Config {
+	config := Config{
+		BaseURL:           DefaultBaseURL,
+		APIVersion:        DefaultAPIVersion,
+		Timeout:           DefaultTimeout,
+		OperationTimeouts: map[Operation]time.Duration{},
+		UserAgent:         DefaultUserAgent,
+		Transport:         http.DefaultTransport,
+		RetryPolicy:       DefaultRetryPolicy,
+		CountryRules:      countryrules.Default,
+	}
+
+	if value, ok := os.LookupEnv("ACCOUNT_API_BASE_URL"); ok {
+		config.BaseURL = value
+	}
+	if value, ok := os.LookupEnv("ACCOUNT_API_VERSION"); ok {
+		config.APIVersion = value
+	}
+	if value, ok := os.LookupEnv("ACCOUNT_API_USER_AGENT"); ok {
+		config.UserAgent = value
+	}
+	if timeout, ok := durationFromEnv("ACCOUNT_API_TIMEOUT"); ok {
+		config.Timeout = timeout
+	}
+	if value, ok := os.LookupEnv("ACCOUNT_API_RETRY_MAX_ATTEMPTS"); ok {
+		if attempts, err := strconv.Atoi(value); err == nil {
+			config.RetryPolicy.MaxAttempts = attempts
+		}
+	}
+	threshold, cooldown := DefaultBreakerThreshold, DefaultBreakerCooldown
+	if value, ok := os.LookupEnv("ACCOUNT_API_BREAKER_THRESHOLD"); ok {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			threshold = parsed
+		}
+	}
+	if parsed, ok := durationFromEnv("ACCOUNT_API_BREAKER_COOLDOWN"); ok {
+		cooldown = parsed
+	}
+	if threshold > 0 {
+		config.Breaker = NewCircuitBreaker(threshold, cooldown)
+	}
+	for _, operation := range []Operation{OperationFetch, OperationCreate, OperationDelete, OperationList, OperationUpdate, OperationValidate} {
+		if timeout, ok := durationFromEnv("ACCOUNT_API_" + strings.ToUpper(string(operation)) + "_TIMEOUT"); ok {
+			config.OperationTimeouts[operation] = timeout
+		}
+	}
+
+	return config
+}
+
+func durationFromEnv(key string) (time.Duration, bool) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return 0, false
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return duration, true
+}
+
+// timeout returns the timeout configured for the operation, falling back to Timeout.
+func (c Config) timeout(operation Operation) time.Duration {
+	if timeout, ok := c.OperationTimeouts[operation]; ok {
+		return timeout
+	}
+	return c.Timeout
+}
+
+// retryPolicy returns the policy configured for the operation, falling back to
+// RetryPolicy when the call is idempotent and to NoRetry otherwise.
+func (c Config) retryPolicy(operation Operation, idempotent bool) RetryPolicy {
+	if policy, ok := c.OperationRetryPolicies[operation]; ok {
+		return policy
+	}
+	if idempotent {
+		return c.RetryPolicy
+	}
+	return NoRetry
+}
+
+// accountsURL is the address of the organisation accounts resource.
+func (c Config) accountsURL() string {
+	return c.apiURL("/organisation/accounts")
+}
+
+// apiURL is the address of a resource of the versioned API, e.g. /validations/gbdsc.
+func (c Config) apiURL(path string) string {
+	url := strings.TrimRight(c.BaseURL, "/")
+	if version := strings.Trim(c.APIVersion, "/"); len(version) > 0 {
+		url += "/" + version
+	}
+	return url + path
+}
+
+// WithConfig replaces the whole Config, including the environment defaults.
+func WithConfig(config Config) Option {
+	return func(c *Config) {
+		*c = config
+	}
+}
+
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.BaseURL = baseURL
+	}
+}
+
+func WithAPIVersion(version string) Option {
+	return func(c *Config) {
+		c.APIVersion = version
+	}
+}
+
+// WithTimeout sets the timeout of every operation without its own timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
+func WithOperationTimeout(operation Operation, timeout time.Duration) Option {
+	return func(c *Config) {
+		if c.OperationTimeouts == nil {
+			c.OperationTimeouts = map[Operation]time.Duration{}
+		}
+		c.OperationTimeouts[operation] = timeout
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithTransport injects the RoundTripper used for every upstream request,
+// e.g. a test stub or an instrumented transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+// WithRetryPolicy sets the policy of every operation without its own policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Config) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithOperationRetryPolicy sets the policy of one operation. It applies even to
+// the operations that are not retried by default, e.g. creates without an ID.
+func WithOperationRetryPolicy(operation Operation, policy RetryPolicy) Option {
+	return func(c *Config) {
+		if c.OperationRetryPolicies == nil {
+			c.OperationRetryPolicies = map[Operation]RetryPolicy{}
+		}
+		c.OperationRetryPolicies[operation] = policy
+	}
+}
+
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Config) {
+		c.Metrics = metrics
+	}
+}
+
+// WithCircuitBreaker replaces the circuit breaker; nil turns it off. A breaker
+// may be shared by several clients calling the same account API.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(c *Config) {
+		c.Breaker = breaker
+	}
+}
+
+// WithCountryRules replaces the country rules registry; nil turns the country checks off.
+func WithCountryRules(registry *countryrules.Registry) Option {
+	return func(c *Config) {
+		c.CountryRules = registry
+	}
+}
+
+// WithModulusChecker checks UK accounts with the offline weight table instead of
+// calling the validation endpoint.
+func WithModulusChecker(checker *modulus.Checker) Option {
+	return func(c *Config) {
+		c.ModulusChecker = checker
+	}
+}
+
+// WithModulusCheckOnCreate rejects GB creates whose account number fails the
+// modulus check of their sort code.
+func WithModulusCheckOnCreate(checker *modulus.Checker) Option {
+	return func(c *Config) {
+		c.ModulusChecker = checker
+		c.ModulusCheckOnCreate = true
+	}
+}
+
+func WithBankDirectory(directory *bankdir.Directory) Option {
+	return func(c *Config) {
+		c.BankDirectory = directory
+	}
+}
@@ -0,0 +1,83 @@
+package accounts
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestConfig_FromEnvironment(t *testing.T) {
+	t.Setenv("ACCOUNT_API_BASE_URL", "http://accountapi:8080/")
+	t.Setenv("ACCOUNT_API_VERSION", "v2")
+	t.Setenv("ACCOUNT_API_TIMEOUT", "3s")
+	t.Setenv("ACCOUNT_API_CREATE_TIMEOUT", "5s")
+	t.Setenv("ACCOUNT_API_DELETE_TIMEOUT", "not a duration")
+
+	config := DefaultConfig()
+
+	if url := config.accountsURL(); url != "http://accountapi:8080/v2/organisation/accounts" {
+		t.Errorf("Expected %s, returned %s", "http://accountapi:8080/v2/organisation/accounts", url)
+	}
+
+	var testCases = []struct {
+		operation        Operation
+		expected_timeout time.Duration
+	}{
+		{OperationFetch, 3 * time.Second},
+		{OperationCreate, 5 * time.Second},
+		{OperationDelete, 3 * time.Second}}
+
+	for _, tc := range testCases {
+		if timeout := config.timeout(tc.operation); timeout != tc.expected_timeout {
+			t.Errorf("%s: expected %v, returned %v", tc.operation, tc.expected_timeout, timeout)
+		}
+	}
+}
+
+func TestClient_WithTransportAndUserAgent(t *testing.T) {
+	var userAgent, url string
+	client := NewClient(
+		WithBaseURL("http://stub"),
+		WithAPIVersion(""),
+		WithUserAgent("reconciliation-job"),
+		WithTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			userAgent = r.UserAgent()
+			url = r.URL.String()
+			w := httptest.NewRecorder()
+			w.WriteHeader(http.StatusNoContent)
+			return w.Result(), nil
+		})))
+
+	if _, err := client.Delete(context.Background(), accountId, 0); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if userAgent != "reconciliation-job" {
+		t.Errorf("Expected %s, returned %s", "reconciliation-job", userAgent)
+	}
+	if url != "http://stub/organisation/accounts/"+accountId+"?version=0" {
+		t.Errorf("Unexpected url %s", url)
+	}
+}
+
+func TestClient_OperationTimeout(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	client = NewClient(WithConfig(client.config), WithOperationTimeout(OperationFetch, 10*time.Millisecond))
+
+	if _, err := client.Fetch(context.Background(), accountId); err == nil {
+		t.Errorf("Expected timeout error")
+	}
+}
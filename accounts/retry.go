@@ -0,0 +1,194 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how an operation is retried after a transient failure:
+// a transport error or a response whose status is in RetryableStatus.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt too; 1 or less disables retries.
+	MaxAttempts int
+
+	// BaseDelay is doubled after every attempt and capped at MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// Jitter is the fraction of the delay that is randomised, from 0 to 1.
+	Jitter float64
+
+	RetryableStatus []int
+
+	// RespectRetryAfter waits at least the Retry-After of the response. When it
+	// asks for longer than MaxDelay the error is returned without retrying.
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy is applied to the idempotent operations unless configured otherwise.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Jitter:      0.2,
+	RetryableStatus: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	RespectRetryAfter: true,
+}
+
+// NoRetry makes a single attempt.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// retryable reports whether the status of a failed response may be retried.
+func (p RetryPolicy) retryable(statusCode int) bool {
+	for _, status := range p.RetryableStatus {
+		if status == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the delay before retrying a failed attempt, counted from 1, and
+// whether the failure may be retried at all. Errors without a status, such as
+// refused connections and timeouts of a single attempt, are retryable.
+func (p RetryPolicy) next(attempt int, err error, header http.Header) (time.Duration, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && !p.retryable(apiErr.StatusCode) {
+		return 0, false
+	}
+
+	delay := p.backoff(attempt)
+	if after, ok := retryAfter(header); ok && p.RespectRetryAfter {
+		if p.MaxDelay > 0 && after > p.MaxDelay {
+			return 0, false
+		}
+		if after > delay {
+			delay = after
+		}
+	}
+	return delay, true
+}
+
+// backoff returns the delay before the given retry, counted from 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay = delay * (1 - jitter + jitter*rand.Float64())
+	}
+	return time.Duration(delay)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// sleep waits for the delay unless the context ends first.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Metrics receives the retries made by the Client, e.g. to export them as counters.
+type Metrics interface {
+	// Retry is called before every retry, with the attempt about to be made and
+	// the error of the previous one.
+	Retry(operation Operation, attempt int, err error)
+
+	// RetriesExhausted is called when the last attempt allowed by the policy fails.
+	RetriesExhausted(operation Operation, err error)
+}
+
+// RetryStats is a Metrics counting retries per operation.
+type RetryStats struct {
+	mu        sync.Mutex
+	retries   map[Operation]int64
+	exhausted map[Operation]int64
+}
+
+func NewRetryStats() *RetryStats {
+	return &RetryStats{
+		retries:   map[Operation]int64{},
+		exhausted: map[Operation]int64{},
+	}
+}
+
+func (s *RetryStats) Retry(operation Operation, attempt int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries[operation]++
+}
+
+func (s *RetryStats) RetriesExhausted(operation Operation, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exhausted[operation]++
+}
+
+// Retries returns the number of retries made for the operation.
+func (s *RetryStats) Retries(operation Operation) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.retries[operation]
+}
+
+// Exhausted returns how many times the operation failed after its last attempt.
+func (s *RetryStats) Exhausted(operation Operation) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exhausted[operation]
+}
+
+// Snapshot returns the counters keyed by operation, e.g. for expvar.Func.
+func (s *RetryStats) Snapshot() interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := map[string]map[Operation]int64{
+		"retries":   {},
+		"exhausted": {},
+	}
+	for operation, count := range s.retries {
+		snapshot["retries"][operation] = count
+	}
+	for operation, count := range s.exhausted {
+		snapshot["exhausted"][operation] = count
+	}
+	return snapshot
+}
@@ -0,0 +1,235 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/client-library/domain"
+)
+
+var fastRetry = RetryPolicy{
+	MaxAttempts:       3,
+	BaseDelay:         time.Millisecond,
+	MaxDelay:          10 * time.Millisecond,
+	RetryableStatus:   DefaultRetryPolicy.RetryableStatus,
+	RespectRetryAfter: true,
+}
+
+// flakyServer answers status to the first failures requests and then hands
+// over to handler. It returns the client and the number of requests received.
+func flakyServer(t *testing.T, failures int32, status int, handler http.HandlerFunc, opts ...Option) (*Client, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error_message":"upstream failure"}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{WithBaseURL(server.URL), WithRetryPolicy(fastRetry)}, opts...)
+	return NewClient(opts...), &requests
+}
+
+func fetchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"data":{"id":"` + accountId + `","version":0,"attributes":{"country":"GB"}}}`))
+}
+
+func createHandler(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	var request domain.CreateAccountBackendRequest
+	json.Unmarshal(body, &request)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(domain.CreateAccountBackendResult{Data: request.Data})
+}
+
+var retryCreateRequest = domain.CreateAccountRequest{
+	OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+	Attributes:     domain.Attributes{Country: "GB", BankID: "400300", Bic: "NWBKGB22", Name: []string{"Fábio"}},
+}
+
+func TestClient_RetriesTransientFailures(t *testing.T) {
+	stats := NewRetryStats()
+	client, requests := flakyServer(t, 2, http.StatusServiceUnavailable, fetchHandler, WithMetrics(stats))
+
+	if _, err := client.Fetch(context.Background(), accountId); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if *requests != 3 {
+		t.Errorf("Expected %d requests, returned %d", 3, *requests)
+	}
+	if stats.Retries(OperationFetch) != 2 || stats.Exhausted(OperationFetch) != 0 {
+		t.Errorf("Expected 2 retries and none exhausted, returned %v", stats.Snapshot())
+	}
+}
+
+func TestClient_RetriesExhausted(t *testing.T) {
+	stats := NewRetryStats()
+	client, requests := flakyServer(t, 10, http.StatusBadGateway, fetchHandler, WithMetrics(stats))
+
+	_, err := client.Fetch(context.Background(), accountId)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable, returned %v", err)
+	}
+
+	if *requests != 3 {
+		t.Errorf("Expected %d requests, returned %d", 3, *requests)
+	}
+	if stats.Retries(OperationFetch) != 2 || stats.Exhausted(OperationFetch) != 1 {
+		t.Errorf("Expected 2 retries and 1 exhausted, returned %v", stats.Snapshot())
+	}
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	client, requests := flakyServer(t, 10, http.StatusNotFound, fetchHandler)
+
+	if _, err := client.Fetch(context.Background(), accountId); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, returned %v", err)
+	}
+	if *requests != 1 {
+		t.Errorf("Expected %d request, returned %d", 1, *requests)
+	}
+}
+
+func TestClient_RetriesTransportErrors(t *testing.T) {
+	var requests int32
+	client := NewClient(
+		WithRetryPolicy(fastRetry),
+		WithTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&requests, 1) == 1 {
+				return nil, errors.New("connection reset by peer")
+			}
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       http.NoBody,
+				Request:    r,
+			}, nil
+		})))
+
+	if _, err := client.Delete(context.Background(), accountId, 0); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if requests != 2 {
+		t.Errorf("Expected %d requests, returned %d", 2, requests)
+	}
+}
+
+func TestClient_RetryCreate(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		id                string
+		options           []Option
+		expected_requests int32
+	}{
+		{"WithoutID", "", nil, 1},
+		{"WithID", accountId, nil, 2},
+		{"WithoutIDConfigured", "", []Option{WithOperationRetryPolicy(OperationCreate, fastRetry)}, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, requests := flakyServer(t, 1, http.StatusServiceUnavailable, createHandler, tc.options...)
+
+			request := retryCreateRequest
+			request.ID = tc.id
+			client.Create(context.Background(), request)
+
+			if *requests != tc.expected_requests {
+				t.Errorf("Expected %d requests, returned %d", tc.expected_requests, *requests)
+			}
+		})
+	}
+}
+
+func TestClient_OperationRetryPolicy(t *testing.T) {
+	client, requests := flakyServer(t, 1, http.StatusServiceUnavailable, fetchHandler,
+		WithOperationRetryPolicy(OperationFetch, NoRetry))
+
+	if _, err := client.Fetch(context.Background(), accountId); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable, returned %v", err)
+	}
+	if *requests != 1 {
+		t.Errorf("Expected %d request, returned %d", 1, *requests)
+	}
+}
+
+func TestClient_RespectsRetryAfter(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		retryAfter        string
+		expected_requests int32
+		expected_wait     time.Duration
+	}{
+		{"Seconds", "1", 2, time.Second},
+		{"LongerThanMaxDelay", "60", 1, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) == 1 {
+					w.Header().Set("Retry-After", tc.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				fetchHandler(w, r)
+			}))
+			t.Cleanup(server.Close)
+
+			policy := fastRetry
+			policy.MaxDelay = 2 * time.Second
+			client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(policy))
+
+			start := time.Now()
+			client.Fetch(context.Background(), accountId)
+
+			if requests != tc.expected_requests {
+				t.Errorf("Expected %d requests, returned %d", tc.expected_requests, requests)
+			}
+			if elapsed := time.Since(start); elapsed < tc.expected_wait {
+				t.Errorf("Expected to wait %s, waited %s", tc.expected_wait, elapsed)
+			}
+		})
+	}
+}
+
+func TestClient_RetryStopsWhenCancelled(t *testing.T) {
+	policy := fastRetry
+	policy.BaseDelay = time.Minute
+	policy.MaxDelay = time.Minute
+	client, requests := flakyServer(t, 10, http.StatusServiceUnavailable, fetchHandler, WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Fetch(ctx, accountId); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable, returned %v", err)
+	}
+	if *requests != 1 {
+		t.Errorf("Expected %d request, returned %d", 1, *requests)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.5}
+
+	for retry, expected := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 5: time.Second} {
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(retry)
+			if delay < expected/2 || delay > expected {
+				t.Errorf("Expected retry %d to wait between %s and %s, returned %s", retry, expected/2, expected, delay)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/client-library/domain"
+)
+
+const (
+	ValidationSourceLocal  = "local"
+	ValidationSourceRemote = "remote"
+)
+
+// ValidateUKAccount checks a sort code and account number with the account API's
+// GBDSC validation endpoint. A 2xx answer means valid and a 400 or 422 invalid.
+func (c *Client) ValidateUKAccount(ctx context.Context, sortCode string, accountNumber string) (bool, error) {
+	url := c.config.apiURL(fmt.Sprintf("/validations/gbdsc/sortcodes/%s/accountnumbers/%s", sortCode, accountNumber))
+
+	_, err := c.do(ctx, OperationValidate, "", http.MethodGet, url, nil)
+	if err == nil {
+		return true, nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusBadRequest || apiErr.StatusCode == http.StatusUnprocessableEntity) {
+		return false, nil
+	}
+	return false, err
+}
+
+// CheckUKAccount prefers the offline modulus check when a weight table is
+// configured and falls back to ValidateUKAccount otherwise.
+func (c *Client) CheckUKAccount(ctx context.Context, sortCode string, accountNumber string) (*domain.AccountValidationResult, error) {
+	if c.config.ModulusChecker != nil {
+		valid, err := c.config.ModulusChecker.Check(sortCode, accountNumber)
+		if err != nil {
+			return nil, &APIError{StatusCode: http.StatusBadRequest, Message: err.Error(), Operation: OperationValidate}
+		}
+		return &domain.AccountValidationResult{Valid: valid, Source: ValidationSourceLocal}, nil
+	}
+
+	valid, err := c.ValidateUKAccount(ctx, sortCode, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AccountValidationResult{Valid: valid, Source: ValidationSourceRemote}, nil
+}
+
+// modulusCheck validates the account number of a GB create against its sort code.
+func (c *Client) modulusCheck(attributes domain.Attributes) []domain.FieldError {
+	if !c.config.ModulusCheckOnCreate || c.config.ModulusChecker == nil ||
+		attributes.Country != "GB" || !strings.EqualFold(attributes.BankIDCode, "GBDSC") || len(attributes.AccountNumber) == 0 {
+		return nil
+	}
+
+	valid, err := c.config.ModulusChecker.Check(attributes.BankID, attributes.AccountNumber)
+	if err == nil && valid {
+		return nil
+	}
+
+	return []domain.FieldError{{Field: "account_number", Rule: domain.RuleInvalid, Value: attributes.AccountNumber,
+		Message: "account_number in body fails the modulus check for sort code " + attributes.BankID}}
+}
@@ -0,0 +1,80 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/client-library/domain"
+	"github.com/client-library/modulus"
+)
+
+func testChecker(t *testing.T) *modulus.Checker {
+	checker, err := modulus.LoadWeights(strings.NewReader("089000 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7 1"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	return checker
+}
+
+func TestClient_CheckUKAccount(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		local           bool
+		account_number  string
+		expected_valid  bool
+		expected_source string
+	}{
+		{"LocalValid", true, "66374958", true, ValidationSourceLocal},
+		{"LocalInvalid", true, "66374959", false, ValidationSourceLocal},
+		{"RemoteValid", false, "66374958", true, ValidationSourceRemote},
+		{"RemoteInvalid", false, "66374959", false, ValidationSourceRemote}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if tc.local {
+					t.Errorf("Unexpected request %s", r.URL.Path)
+				}
+				if r.URL.Path != "/v1/validations/gbdsc/sortcodes/089999/accountnumbers/"+tc.account_number {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				if tc.account_number != "66374958" {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error_message":"account number failed the modulus check"}`))
+				}
+			})
+			if tc.local {
+				client = NewClient(WithConfig(client.config), WithModulusChecker(testChecker(t)))
+			}
+
+			result, err := client.CheckUKAccount(context.Background(), "089999", tc.account_number)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			if result.Valid != tc.expected_valid || result.Source != tc.expected_source {
+				t.Errorf("Expected %v %s, returned %+v", tc.expected_valid, tc.expected_source, result)
+			}
+		})
+	}
+}
+
+func TestClient_CreateWithModulusCheck(t *testing.T) {
+	client := stubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	client = NewClient(WithConfig(client.config), WithModulusCheckOnCreate(testChecker(t)))
+
+	_, err := client.Create(context.Background(), domain.CreateAccountRequest{
+		OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+		Attributes: domain.Attributes{Country: "GB", BankID: "089999", BankIDCode: "GBDSC", Bic: "NWBKGB22",
+			AccountNumber: "66374959", Name: []string{"Fábio"}},
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || len(apiErr.FieldErrors) != 1 || apiErr.FieldErrors[0].Field != "account_number" {
+		t.Errorf("Expected account_number modulus error, returned %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package bankdir looks up banks by BIC and national bank code in a local
+// directory file.
+package bankdir
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/client-library/domain"
+)
+
+var (
+	ErrUnknownBic      = errors.New("bankdir: unknown bic")
+	ErrCountryMismatch = errors.New("bankdir: bic does not belong to country")
+	ErrBankIDMismatch  = errors.New("bankdir: bank_id does not belong to bic")
+)
+
+// Directory indexes banks by BIC. A BIC may be listed once per national bank
+// code, e.g. once for every sort code of a UK bank.
+type Directory struct {
+	banks map[string][]domain.Bank
+}
+
+func New(banks []domain.Bank) *Directory {
+	directory := &Directory{banks: map[string][]domain.Bank{}}
+	for _, bank := range banks {
+		bank.Bic = normaliseBic(bank.Bic)
+		directory.banks[bank.Bic] = append(directory.banks[bank.Bic], bank)
+	}
+	return directory
+}
+
+// LoadFile reads a .json or .csv directory file, see LoadJSON and LoadCSV.
+func LoadFile(path string) (*Directory, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return LoadJSON(file)
+	}
+	return LoadCSV(file)
+}
+
+// LoadJSON reads an array of domain.Bank objects.
+func LoadJSON(r io.Reader) (*Directory, error) {
+	var banks []domain.Bank
+	if err := json.NewDecoder(r).Decode(&banks); err != nil {
+		return nil, fmt.Errorf("bankdir: %w", err)
+	}
+	return New(banks), nil
+}
+
+// LoadCSV reads rows with the header bic,country,bank_id,bank_id_code,name,branch.
+func LoadCSV(r io.Reader) (*Directory, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 6
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("bankdir: %w", err)
+	}
+
+	var banks []domain.Bank
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "bic") {
+			continue
+		}
+		banks = append(banks, domain.Bank{
+			Bic:        record[0],
+			Country:    record[1],
+			BankID:     record[2],
+			BankIDCode: record[3],
+			Name:       record[4],
+			Branch:     record[5],
+		})
+	}
+	return New(banks), nil
+}
+
+// normaliseBic upper cases the BIC and pads a BIC8 to its head office BIC11.
+func normaliseBic(bic string) string {
+	bic = strings.ToUpper(strings.TrimSpace(bic))
+	if len(bic) == 8 {
+		bic += "XXX"
+	}
+	return bic
+}
+
+// Lookup returns the banks listed under the BIC. An unknown branch BIC11
+// falls back to its head office.
+func (d *Directory) Lookup(bic string) ([]domain.Bank, bool) {
+	bic = normaliseBic(bic)
+	if banks, ok := d.banks[bic]; ok {
+		return banks, true
+	}
+	if len(bic) == 11 {
+		banks, ok := d.banks[bic[:8]+"XXX"]
+		return banks, ok
+	}
+	return nil, false
+}
+
+// Find returns the bank of a BIC and national bank ID; an empty bankID
+// returns the first bank listed under the BIC.
+func (d *Directory) Find(bic string, bankID string) (domain.Bank, bool) {
+	banks, ok := d.Lookup(bic)
+	if !ok {
+		return domain.Bank{}, false
+	}
+	for _, bank := range banks {
+		if len(bankID) == 0 || bank.BankID == bankID {
+			return bank, true
+		}
+	}
+	return domain.Bank{}, false
+}
+
+// Validate checks that the BIC exists, belongs to the country, both by its
+// country code and by its directory entry, and, when given, lists the national
+// bank ID.
+func (d *Directory) Validate(bic string, country string, bankID string) error {
+	banks, ok := d.Lookup(bic)
+	if !ok {
+		return fmt.Errorf("%w %s", ErrUnknownBic, bic)
+	}
+	if len(country) > 0 && (normaliseBic(bic)[4:6] != country || banks[0].Country != country) {
+		return fmt.Errorf("%w %s: %s", ErrCountryMismatch, country, bic)
+	}
+	if len(bankID) > 0 {
+		if _, ok := d.Find(bic, bankID); !ok {
+			return fmt.Errorf("%w %s: %s", ErrBankIDMismatch, bic, bankID)
+		}
+	}
+	return nil
+}
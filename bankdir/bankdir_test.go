@@ -0,0 +1,62 @@
+package bankdir
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/client-library/domain"
+)
+
+func TestLoadFile(t *testing.T) {
+	for _, path := range []string{"testdata/banks.csv", "testdata/banks.json"} {
+		t.Run(path, func(t *testing.T) {
+			directory, err := LoadFile(path)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			bank, ok := directory.Find("NWBKGB22XXX", "400300")
+			if !ok || bank.Name != "National Westminster Bank" || bank.Branch != "London City" {
+				t.Errorf("Unexpected bank %+v", bank)
+			}
+		})
+	}
+}
+
+func TestDirectory_Validate(t *testing.T) {
+	directory, err := LoadFile("testdata/banks.csv")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var testCases = []struct {
+		name           string
+		bic            string
+		country        string
+		bank_id        string
+		expected_error error
+	}{
+		{"ShouldBeValid", "NWBKGB22", "GB", "400300", nil},
+		{"BranchBicFallsBackToHeadOffice", "nwbkgb22abc", "GB", "601613", nil},
+		{"WithoutBankID", "DEUTDEFF", "DE", "", nil},
+		{"UnknownBic", "BARCGB22", "GB", "", ErrUnknownBic},
+		{"CountryDoesNotMatch", "DEUTDEFF", "GB", "", ErrCountryMismatch},
+		{"BankIDDoesNotMatch", "NWBKGB22", "GB", "200000", ErrBankIDMismatch}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := directory.Validate(tc.bic, tc.country, tc.bank_id); !errors.Is(err, tc.expected_error) {
+				t.Errorf("Expected %v, returned %v", tc.expected_error, err)
+			}
+		})
+	}
+}
+
+func TestDirectory_ValidateBicCountryCode(t *testing.T) {
+	// an entry filed under the wrong country still fails on the BIC's own country code
+	directory := New([]domain.Bank{{Bic: "BNPAFRPP", Country: "GB", Name: "BNP Paribas"}})
+
+	if err := directory.Validate("BNPAFRPP", "GB", ""); !errors.Is(err, ErrCountryMismatch) {
+		t.Errorf("Expected %v, returned %v", ErrCountryMismatch, err)
+	}
+}
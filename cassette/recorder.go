@@ -0,0 +1,396 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mode selects what a Recorder does with each request.
+type Mode string
+
+const (
+	// ModeRecord sends requests upstream and saves the exchanges on Stop.
+	ModeRecord Mode = "record"
+	// ModeReplay answers from the cassette and never calls upstream.
+	ModeReplay Mode = "replay"
+	// ModePassthrough sends requests upstream without recording them.
+	ModePassthrough Mode = "passthrough"
+)
+
+// ErrNoInteraction is returned in replay mode for a request the cassette has no answer for.
+var ErrNoInteraction = errors.New("cassette: no recorded interaction matches the request")
+
+// Match selects the parts of a request compared with the recorded ones.
+type Match struct {
+	Method bool
+	Path   bool
+	Query  bool
+	Body   bool
+}
+
+var MatchAll = Match{Method: true, Path: true, Query: true, Body: true}
+
+// DefaultRedactedFields are the JSON fields whose values change on every run.
+var DefaultRedactedFields = []string{"id", "created_on", "modified_on"}
+
+// Recorder is an http.RoundTripper recording to or replaying from a cassette.
+type Recorder struct {
+	path      string
+	mode      Mode
+	match     Match
+	transport http.RoundTripper
+	redactor  *redactor
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// Option changes a Recorder built by New.
+type Option func(*Recorder)
+
+// WithMatch replaces MatchAll, e.g. to ignore bodies.
+func WithMatch(match Match) Option {
+	return func(r *Recorder) {
+		r.match = match
+	}
+}
+
+// WithRedactedFields replaces DefaultRedactedFields. Only string values are
+// redacted; numbers, such as version, are recorded and matched as they are.
+func WithRedactedFields(fields ...string) Option {
+	return func(r *Recorder) {
+		r.redactor = newRedactor(fields)
+	}
+}
+
+// WithTransport sets the RoundTripper used to record and pass through,
+// http.DefaultTransport by default.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// New returns a Recorder for the cassette at path, read at once in replay mode.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		match:     MatchAll,
+		transport: http.DefaultTransport,
+		redactor:  newRedactor(DefaultRedactedFields),
+		cassette:  &Cassette{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeReplay:
+		cassette, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.used = make([]bool, len(cassette.Interactions))
+	case ModeRecord, ModePassthrough:
+	default:
+		return nil, fmt.Errorf("cassette: unknown mode %q", mode)
+	}
+
+	return r, nil
+}
+
+// Stop saves the recorded exchanges in record mode and does nothing otherwise.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModePassthrough {
+		return r.transport.RoundTrip(req)
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	request := Request{
+		Method: req.Method,
+		Body:   r.redactor.body(body),
+		Path:   r.redactor.url(req.URL.Path),
+		Query:  r.redactor.url(req.URL.RawQuery),
+	}
+	if r.mode == ModeReplay {
+		defer r.mu.Unlock()
+		return r.replay(req, request)
+	}
+	r.mu.Unlock()
+
+	return r.record(req, request)
+}
+
+// record sends the request upstream without holding the lock, so concurrent
+// calls are not serialised, and appends the exchange once it is complete.
+func (r *Recorder) record(req *http.Request, request Request) (*http.Response, error) {
+	response, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: request,
+		Response: Response{
+			StatusCode: response.StatusCode,
+			Header:     responseHeader(response.Header),
+			Body:       r.redactor.body(string(body)),
+		},
+	})
+	r.mu.Unlock()
+
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return response, nil
+}
+
+// replay answers with the first unused interaction matching the request,
+// putting back the live values of the redacted fields.
+func (r *Recorder) replay(req *http.Request, request Request) (*http.Response, error) {
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(interaction.Request, request) {
+			continue
+		}
+		r.used[i] = true
+
+		header := http.Header{}
+		for name, values := range interaction.Response.Header {
+			header[http.CanonicalHeaderKey(name)] = values
+		}
+		body := r.redactor.restore(interaction.Response.Body)
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+}
+
+func (r *Recorder) matches(recorded Request, request Request) bool {
+	if r.match.Method && recorded.Method != request.Method {
+		return false
+	}
+	if r.match.Path && recorded.Path != request.Path {
+		return false
+	}
+	if r.match.Query && !sameQuery(recorded.Query, request.Query) {
+		return false
+	}
+	if r.match.Body && recorded.Body != request.Body {
+		return false
+	}
+	return true
+}
+
+// sameQuery compares two raw queries regardless of the parameter order.
+func sameQuery(a string, b string) bool {
+	valuesA, errA := url.ParseQuery(a)
+	valuesB, errB := url.ParseQuery(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return valuesA.Encode() == valuesB.Encode()
+}
+
+// readBody reads the request body and puts it back for the upstream transport.
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+const (
+	// redactedTime replaces every redacted timestamp, so the cassette does not
+	// change between recordings.
+	redactedTime = "2000-01-01T00:00:00Z"
+	// redactedPrefix starts the placeholders of the redacted strings that are
+	// neither UUIDs nor timestamps.
+	redactedPrefix = "redacted-"
+)
+
+// redactor replaces the values of the redacted JSON fields and every UUID in a
+// URL or body with placeholders. The same live value always gets the same placeholder,
+// numbered in the order they are seen, so a replay that makes the same requests
+// with fresh IDs matches the recording and gets its own IDs back.
+type redactor struct {
+	fields       map[string]bool
+	placeholders map[string]string
+	live         map[string]string
+}
+
+func newRedactor(fields []string) *redactor {
+	r := &redactor{
+		fields:       map[string]bool{},
+		placeholders: map[string]string{},
+		live:         map[string]string{},
+	}
+	for _, field := range fields {
+		r.fields[field] = true
+	}
+	return r
+}
+
+// placeholder returns a UUID, a fixed timestamp or a numbered string for value,
+// so the placeholder still parses like the value it stands for.
+func (r *redactor) placeholder(value string) string {
+	if placeholder, ok := r.placeholders[value]; ok {
+		return placeholder
+	}
+	if _, ok := r.live[value]; ok {
+		// a placeholder replayed back to the client and sent again
+		return value
+	}
+	if _, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return redactedTime
+	}
+
+	var placeholder string
+	if uuidPattern.MatchString(value) && len(value) == 36 {
+		placeholder = fmt.Sprintf("00000000-0000-4000-8000-%012d", len(r.placeholders)+1)
+	} else {
+		placeholder = fmt.Sprintf("%s%d", redactedPrefix, len(r.placeholders)+1)
+	}
+	r.placeholders[value] = placeholder
+	r.live[placeholder] = value
+	return placeholder
+}
+
+func (r *redactor) url(value string) string {
+	return uuidPattern.ReplaceAllStringFunc(value, r.placeholder)
+}
+
+// body redacts the fields of a JSON body, then every UUID left in it, such as
+// the ones in links.
+func (r *redactor) body(body string) string {
+	redacted := r.rewrite(body, func(value interface{}) interface{} {
+		if value, ok := value.(string); ok {
+			return r.placeholder(value)
+		}
+		return value
+	})
+	return r.url(redacted)
+}
+
+// restore puts the live values back into a replayed body: the client's own
+// values for the placeholders of the UUIDs and of the other redacted strings.
+// The placeholders of values the client has not sent are kept, and take up
+// their number as they did when recording. Timestamps have no live value and
+// are replayed as redactedTime.
+func (r *redactor) restore(body string) string {
+	body = uuidPattern.ReplaceAllStringFunc(body, func(placeholder string) string {
+		r.replayed(placeholder)
+		return r.live[placeholder]
+	})
+	if !strings.Contains(body, redactedPrefix) {
+		return body
+	}
+
+	return r.rewrite(body, func(value interface{}) interface{} {
+		if value, ok := value.(string); ok && strings.HasPrefix(value, redactedPrefix) {
+			r.replayed(value)
+			return r.live[value]
+		}
+		return value
+	})
+}
+
+func (r *redactor) replayed(placeholder string) {
+	if _, ok := r.live[placeholder]; ok || placeholder == redactedTime {
+		return
+	}
+	r.placeholders[placeholder] = placeholder
+	r.live[placeholder] = placeholder
+}
+
+// rewrite applies fn to the value of every redacted field of a JSON body,
+// visiting the fields in name order so the placeholders are numbered the same
+// way on every run.
+func (r *redactor) rewrite(body string, fn func(interface{}) interface{}) string {
+	var document interface{}
+	if len(body) == 0 || json.Unmarshal([]byte(body), &document) != nil {
+		return body
+	}
+
+	rewritten, err := json.Marshal(r.visit(document, fn))
+	if err != nil {
+		return body
+	}
+	return string(rewritten)
+}
+
+func (r *redactor) visit(value interface{}, fn func(interface{}) interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if r.fields[key] {
+				v[key] = fn(v[key])
+			} else {
+				v[key] = r.visit(v[key], fn)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.visit(item, fn)
+		}
+	}
+	return value
+}
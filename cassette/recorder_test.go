@@ -0,0 +1,236 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/client-library/accounts"
+	"github.com/client-library/domain"
+	"github.com/client-library/fakeaccountapi"
+
+	"github.com/google/uuid"
+)
+
+var organisationId = "84385b9c-176d-11ed-861d-0242ac120002"
+
+func newClient(baseURL string, recorder *Recorder) *accounts.Client {
+	return accounts.NewClient(accounts.WithBaseURL(baseURL), accounts.WithTransport(recorder), accounts.WithRetryPolicy(accounts.NoRetry))
+}
+
+// flow creates, fetches, updates and deletes the account id, returning the fetched account.
+func flow(t *testing.T, client *accounts.Client, id string) *domain.GetAccountByIdResult {
+	ctx := context.Background()
+
+	created, err := client.Create(ctx, domain.CreateAccountRequest{
+		ID:             id,
+		OrganisationID: organisationId,
+		Attributes:     domain.Attributes{Country: "GB", BankID: "400300", Bic: "NWBKGB22", Name: []string{"Fábio"}},
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if created.AccountId != id {
+		t.Errorf("Expected %s, returned %s", id, created.AccountId)
+	}
+
+	fetched, err := client.Fetch(ctx, id)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	iban := "GB11NWBK40030041426819"
+	updated, err := client.Update(ctx, id, fetched.Version, domain.AttributesPatch{Iban: &iban})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if _, err := client.Delete(ctx, id, updated.Version); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	return fetched
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.json")
+
+	server := httptest.NewServer(fakeaccountapi.New())
+	recorder, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	recordedID := uuid.NewString()
+	flow(t, newClient(server.URL, recorder), recordedID)
+	if err := recorder.Stop(); err != nil {
+		t.Fatalf(err.Error())
+	}
+	server.Close()
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if strings.Contains(string(content), recordedID) {
+		t.Errorf("Expected the account id to be redacted from %s", content)
+	}
+	if !strings.Contains(string(content), "00000000-0000-4000-8000-000000000001") || !strings.Contains(string(content), redactedTime) {
+		t.Errorf("Expected id and created_on placeholders in %s", content)
+	}
+
+	recorder, err = New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	replayedID := uuid.NewString()
+	fetched := flow(t, newClient(server.URL, recorder), replayedID)
+
+	// timestamps have no live value and replay as their placeholder
+	if fetched.Attributes.Country != "GB" || fetched.Version != 0 || fetched.CreatedOn.Format(time.RFC3339) != redactedTime {
+		t.Errorf("Unexpected replayed account %+v", fetched)
+	}
+}
+
+func TestRecorder_ReplayWithoutInteraction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := (&Cassette{}).Save(path); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	recorder, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	_, err = newClient("http://localhost:8080", recorder).Fetch(context.Background(), uuid.NewString())
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, returned %v", err)
+	}
+}
+
+func TestRecorder_Match(t *testing.T) {
+	recorded := &Cassette{Interactions: []Interaction{{
+		Request:  Request{Method: http.MethodPost, Path: "/v1/organisation/accounts", Query: "a=1&b=2", Body: `{"name":"recorded"}`},
+		Response: Response{StatusCode: http.StatusTeapot},
+	}}}
+	path := filepath.Join(t.TempDir(), "match.json")
+	if err := recorded.Save(path); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var testCases = []struct {
+		name            string
+		match           Match
+		query           string
+		body            string
+		expected_status int
+	}{
+		{"All", MatchAll, "b=2&a=1", `{"name":"recorded"}`, http.StatusTeapot},
+		{"BodyDiffers", MatchAll, "a=1&b=2", `{"name":"live"}`, 0},
+		{"BodyIgnored", Match{Method: true, Path: true, Query: true}, "a=1&b=2", `{"name":"live"}`, http.StatusTeapot},
+		{"QueryDiffers", MatchAll, "a=2", `{"name":"recorded"}`, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder, err := New(path, ModeReplay, WithMatch(tc.match))
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "http://accountapi/v1/organisation/accounts?"+tc.query, strings.NewReader(tc.body))
+			response, err := recorder.RoundTrip(req)
+
+			status := 0
+			if err == nil {
+				status = response.StatusCode
+			}
+			if status != tc.expected_status {
+				t.Errorf("Expected %d, returned %d %v", tc.expected_status, status, err)
+			}
+		})
+	}
+}
+
+func TestRecorder_Passthrough(t *testing.T) {
+	server := httptest.NewServer(fakeaccountapi.New())
+	t.Cleanup(server.Close)
+
+	path := filepath.Join(t.TempDir(), "passthrough.json")
+	recorder, err := New(path, ModePassthrough)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	flow(t, newClient(server.URL, recorder), uuid.NewString())
+	if err := recorder.Stop(); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected no cassette in passthrough mode, returned %v", err)
+	}
+}
+
+func TestNew_UnknownMode(t *testing.T) {
+	if _, err := New("cassette.json", Mode("rewind")); err == nil {
+		t.Errorf("Expected an error for an unknown mode")
+	}
+}
+
+func TestRedactor_Restore(t *testing.T) {
+	recording := newRedactor([]string{"id", "reference", "created_on"})
+	recorded := recording.body(`{"id":"84385b9c-176d-11ed-861d-0242ac120002","reference":"ref-1","created_on":"2022-08-09T10:00:00Z"}`)
+
+	// a replay sends its own values and gets them back in place of the placeholders
+	replaying := newRedactor([]string{"id", "reference", "created_on"})
+	replaying.body(`{"id":"3a877792-1783-11ed-861d-0242ac120002","reference":"ref-2"}`)
+	restored := replaying.restore(recorded)
+
+	expected := `{"created_on":"` + redactedTime + `","id":"3a877792-1783-11ed-861d-0242ac120002","reference":"ref-2"}`
+	if restored != expected {
+		t.Errorf("Expected %s, returned %s", expected, restored)
+	}
+}
+
+func TestRecorder_RecordsConcurrently(t *testing.T) {
+	// each call is only answered once both reached the server, which a
+	// recorder serialising its upstream calls would never let happen
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	recorder, err := New(filepath.Join(t.TempDir(), "concurrent.json"), ModeRecord)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	httpClient := &http.Client{Transport: recorder, Timeout: 5 * time.Second}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			response, err := httpClient.Get(server.URL)
+			if err == nil {
+				response.Body.Close()
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
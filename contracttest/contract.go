@@ -0,0 +1,351 @@
+// Package contracttest checks that a server behaves like the Form3 account API
+// on the wire: status codes, JSON:API envelopes and error bodies. Run it against
+// fakeaccountapi and, when it is up, the docker-compose accountapi service, so
+// that the fake cannot drift from the real thing:
+//
+//	func TestContract(t *testing.T) {
+//		contracttest.Suite{BaseURL: server.URL, Patch: true}.Run(t)
+//	}
+package contracttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/client-library/domain"
+
+	"github.com/google/uuid"
+)
+
+const accountsPath = "/v1/organisation/accounts"
+
+// Suite is the contract of the account API served at BaseURL.
+type Suite struct {
+	BaseURL string
+
+	// Patch also checks PATCH, which the accountapi image does not serve.
+	Patch bool
+}
+
+// Run skips the suite when BaseURL does not answer, and otherwise checks every
+// operation with fresh account IDs, deleting the accounts it created at the end.
+func (s Suite) Run(t *testing.T) {
+	t.Helper()
+
+	c := &contractClient{baseURL: strings.TrimRight(s.BaseURL, "/"), http: &http.Client{Timeout: 5 * time.Second}}
+	if err := c.reachable(); err != nil {
+		t.Skipf("account API at %s is unreachable: %v", s.BaseURL, err)
+	}
+
+	t.Run("Create", c.testCreate)
+	t.Run("CreateErrors", c.testCreateErrors)
+	t.Run("Fetch", c.testFetch)
+	t.Run("List", c.testList)
+	if s.Patch {
+		t.Run("Patch", c.testPatch)
+	}
+	t.Run("Delete", c.testDelete)
+}
+
+type contractClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+func (c *contractClient) reachable() error {
+	response, err := c.http.Get(c.baseURL + "/v1/health")
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+// envelope is the JSON:API document of a single account, with the version kept
+// as a pointer to tell a missing version from 0.
+type envelope struct {
+	Data struct {
+		domain.Data
+		Version *int64 `json:"version"`
+	} `json:"data"`
+	Links domain.Links `json:"links"`
+}
+
+func (c *contractClient) do(t *testing.T, method string, path string, body interface{}) (int, []byte) {
+	t.Helper()
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if body != nil {
+		req.Header.Set("content-type", "application/vnd.api+json")
+	}
+
+	response, err := c.http.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	return response.StatusCode, responseBody
+}
+
+// expect fails the test unless the status matches and, when message is set,
+// the error_message of the body contains it.
+func expect(t *testing.T, status int, body []byte, expectedStatus int, message string) {
+	t.Helper()
+
+	if status != expectedStatus {
+		t.Fatalf("Expected %d, returned %d %s", expectedStatus, status, body)
+	}
+	if len(message) == 0 {
+		return
+	}
+
+	var exc domain.CustomException
+	if err := json.Unmarshal(body, &exc); err != nil {
+		t.Fatalf("Expected an error_message body, returned %s", body)
+	}
+	if !strings.Contains(exc.ErrorMessage, message) {
+		t.Errorf("Expected error_message containing %q, returned %q", message, exc.ErrorMessage)
+	}
+}
+
+func decode(t *testing.T, body []byte) envelope {
+	t.Helper()
+
+	var result envelope
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Expected an account envelope, returned %s", body)
+	}
+	return result
+}
+
+func newAccount() domain.CreateAccountBackendRequest {
+	return domain.CreateAccountBackendRequest{Data: domain.Data{
+		ID:             uuid.NewString(),
+		OrganisationID: uuid.NewString(),
+		Type:           "accounts",
+		Attributes: domain.Attributes{
+			Country:      "GB",
+			BaseCurrency: "GBP",
+			BankID:       "400300",
+			BankIDCode:   "GBDSC",
+			Bic:          "NWBKGB22",
+			Name:         []string{"Fábio Fragoso Kraemer Moraes"},
+		},
+	}}
+}
+
+// create stores a new account and deletes it when the test ends, whatever version it has by then.
+func (c *contractClient) create(t *testing.T, request domain.CreateAccountBackendRequest) envelope {
+	t.Helper()
+
+	status, body := c.do(t, http.MethodPost, accountsPath, request)
+	expect(t, status, body, http.StatusCreated, "")
+
+	id := request.Data.ID
+	t.Cleanup(func() {
+		status, body := c.do(t, http.MethodGet, accountsPath+"/"+id, nil)
+		if status != http.StatusOK {
+			return
+		}
+		if version := decode(t, body).Data.Version; version != nil {
+			c.do(t, http.MethodDelete, fmt.Sprintf("%s/%s?version=%d", accountsPath, id, *version), nil)
+		}
+	})
+
+	return decode(t, body)
+}
+
+func (c *contractClient) testCreate(t *testing.T) {
+	request := newAccount()
+	created := c.create(t, request)
+
+	if created.Data.ID != request.Data.ID || created.Data.Type != "accounts" || created.Data.OrganisationID != request.Data.OrganisationID {
+		t.Errorf("Unexpected envelope %+v", created.Data)
+	}
+	if created.Data.Version == nil || *created.Data.Version != 0 {
+		t.Errorf("Expected version 0, returned %v", created.Data.Version)
+	}
+	if created.Data.Attributes.Country != "GB" || created.Data.Attributes.BankID != "400300" {
+		t.Errorf("Expected the attributes back, returned %+v", created.Data.Attributes)
+	}
+	if created.Data.CreatedOn.IsZero() {
+		t.Errorf("Expected created_on")
+	}
+	if created.Links.Self != accountsPath+"/"+request.Data.ID {
+		t.Errorf("Expected self link %s, returned %s", accountsPath+"/"+request.Data.ID, created.Links.Self)
+	}
+}
+
+func (c *contractClient) testCreateErrors(t *testing.T) {
+	existing := newAccount()
+	c.create(t, existing)
+
+	noCountry := newAccount()
+	noCountry.Data.Attributes.Country = ""
+
+	badOrganisation := newAccount()
+	badOrganisation.Data.OrganisationID = "0d077184-ca1b-4583-a416-29c9a51cf6e"
+
+	badID := newAccount()
+	badID.Data.ID = "3a877792-1783-11ed-861d-0242ac12000"
+
+	badType := newAccount()
+	badType.Data.Type = "acounts"
+
+	var testCases = []struct {
+		name             string
+		request          domain.CreateAccountBackendRequest
+		expected_status  int
+		expected_message string
+	}{
+		{"Duplicate", existing, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint"},
+		{"CountryIsRequired", noCountry, http.StatusBadRequest, "country in body is required"},
+		{"InvalidOrganisationID", badOrganisation, http.StatusBadRequest, `organisation_id in body must be of type uuid: "0d077184-ca1b-4583-a416-29c9a51cf6e"`},
+		{"InvalidAccountID", badID, http.StatusBadRequest, `id in body must be of type uuid: "3a877792-1783-11ed-861d-0242ac12000"`},
+		{"InvalidType", badType, http.StatusBadRequest, "type in body should be one of [accounts]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := c.do(t, http.MethodPost, accountsPath, tc.request)
+			expect(t, status, body, tc.expected_status, tc.expected_message)
+		})
+	}
+}
+
+func (c *contractClient) testFetch(t *testing.T) {
+	request := newAccount()
+	c.create(t, request)
+
+	status, body := c.do(t, http.MethodGet, accountsPath+"/"+request.Data.ID, nil)
+	expect(t, status, body, http.StatusOK, "")
+	fetched := decode(t, body)
+	if fetched.Data.ID != request.Data.ID || fetched.Data.Version == nil || *fetched.Data.Version != 0 {
+		t.Errorf("Unexpected envelope %+v", fetched.Data)
+	}
+
+	missing := uuid.NewString()
+	status, body = c.do(t, http.MethodGet, accountsPath+"/"+missing, nil)
+	expect(t, status, body, http.StatusNotFound, "record "+missing+" does not exist")
+
+	status, body = c.do(t, http.MethodGet, accountsPath+"/not-a-uuid", nil)
+	expect(t, status, body, http.StatusBadRequest, "id is not a valid uuid")
+}
+
+func (c *contractClient) testList(t *testing.T) {
+	ids := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		request := newAccount()
+		c.create(t, request)
+		ids[request.Data.ID] = true
+	}
+
+	status, body := c.do(t, http.MethodGet, accountsPath+"?page[size]=2", nil)
+	expect(t, status, body, http.StatusOK, "")
+	var page domain.ListAccountsBackendResult
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatalf("Expected a list envelope, returned %s", body)
+	}
+	if len(page.Data) != 2 || len(page.Links.Next) == 0 {
+		t.Errorf("Expected 2 accounts and a next link, returned %d and %+v", len(page.Data), page.Links)
+	}
+	if len(page.Links.Self) == 0 || len(page.Links.First) == 0 || len(page.Links.Last) == 0 {
+		t.Errorf("Expected self, first and last links, returned %+v", page.Links)
+	}
+
+	// other accounts may share the API, so walk every page until the new ones are seen
+	next := accountsPath + "?page[size]=100"
+	for pages := 0; len(next) > 0 && len(ids) > 0 && pages < 1000; pages++ {
+		status, body := c.do(t, http.MethodGet, next, nil)
+		expect(t, status, body, http.StatusOK, "")
+
+		var page domain.ListAccountsBackendResult
+		if err := json.Unmarshal(body, &page); err != nil {
+			t.Fatalf("Expected a list envelope, returned %s", body)
+		}
+		for _, data := range page.Data {
+			delete(ids, data.ID)
+		}
+
+		next = ""
+		if len(page.Links.Next) > 0 {
+			link, err := url.Parse(page.Links.Next)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			next = link.RequestURI()
+		}
+	}
+	if len(ids) > 0 {
+		t.Errorf("Expected every created account to be listed, missing %v", ids)
+	}
+}
+
+func (c *contractClient) testPatch(t *testing.T) {
+	request := newAccount()
+	c.create(t, request)
+
+	patch := domain.UpdateAccountBackendRequest{}
+	patch.Data.ID = request.Data.ID
+	patch.Data.Type = "accounts"
+	patch.Data.Version = 0
+	name := []string{"Fábio Moraes"}
+	patch.Data.Attributes.Name = &name
+
+	status, body := c.do(t, http.MethodPatch, accountsPath+"/"+request.Data.ID, patch)
+	expect(t, status, body, http.StatusOK, "")
+	patched := decode(t, body)
+	if patched.Data.Version == nil || *patched.Data.Version != 1 {
+		t.Errorf("Expected version 1, returned %v", patched.Data.Version)
+	}
+	if len(patched.Data.Attributes.Name) != 1 || patched.Data.Attributes.Name[0] != name[0] || patched.Data.Attributes.Country != "GB" {
+		t.Errorf("Expected the new name and the other attributes kept, returned %+v", patched.Data.Attributes)
+	}
+
+	status, body = c.do(t, http.MethodPatch, accountsPath+"/"+request.Data.ID, patch)
+	expect(t, status, body, http.StatusConflict, "invalid version")
+}
+
+func (c *contractClient) testDelete(t *testing.T) {
+	request := newAccount()
+	c.create(t, request)
+	path := accountsPath + "/" + request.Data.ID
+
+	status, body := c.do(t, http.MethodDelete, path+"?version=1", nil)
+	expect(t, status, body, http.StatusConflict, "invalid version")
+
+	status, body = c.do(t, http.MethodDelete, path+"?version=0", nil)
+	expect(t, status, body, http.StatusNoContent, "")
+	if len(body) > 0 {
+		t.Errorf("Expected an empty body, returned %s", body)
+	}
+
+	status, body = c.do(t, http.MethodDelete, path+"?version=0", nil)
+	expect(t, status, body, http.StatusNotFound, "")
+
+	status, body = c.do(t, http.MethodGet, path, nil)
+	expect(t, status, body, http.StatusNotFound, "record "+request.Data.ID+" does not exist")
+}
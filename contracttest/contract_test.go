@@ -0,0 +1,18 @@
+package contracttest
+
+import (
+	"os"
+	"testing"
+)
+
+// TestAccountAPI runs the contract against the docker-compose accountapi service,
+// or the API named by ACCOUNT_API_CONTRACT_URL, and skips when it is not running.
+// ACCOUNT_API_CONTRACT_PATCH=true also checks PATCH.
+func TestAccountAPI(t *testing.T) {
+	baseURL, ok := os.LookupEnv("ACCOUNT_API_CONTRACT_URL")
+	if !ok {
+		baseURL = "http://localhost:8080"
+	}
+
+	Suite{BaseURL: baseURL, Patch: os.Getenv("ACCOUNT_API_CONTRACT_PATCH") == "true"}.Run(t)
+}
@@ -0,0 +1,97 @@
+package countryrules
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/client-library/domain"
+)
+
+// Registry holds the Rules of each country, keyed by ISO country code.
+type Registry struct {
+	mu    sync.RWMutex
+	rules map[string]Rules
+}
+
+func NewRegistry(rules ...Rules) *Registry {
+	registry := &Registry{rules: map[string]Rules{}}
+	for _, r := range rules {
+		registry.Register(r)
+	}
+	return registry
+}
+
+// Register adds or replaces the rules of a country.
+func (r *Registry) Register(rules Rules) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[rules.Country()] = rules
+}
+
+func (r *Registry) Lookup(country string) (Rules, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rules, ok := r.rules[country]
+	return rules, ok
+}
+
+// Normalise applies the rules of the attributes' country; attributes of
+// countries without rules are returned unchanged.
+func (r *Registry) Normalise(attributes domain.Attributes) domain.Attributes {
+	if rules, ok := r.Lookup(attributes.Country); ok {
+		return rules.Normalise(attributes)
+	}
+	return attributes
+}
+
+// Validate applies the rules of the attributes' country; countries without
+// rules are left to the account API.
+func (r *Registry) Validate(attributes domain.Attributes) []domain.FieldError {
+	if rules, ok := r.Lookup(attributes.Country); ok {
+		return rules.Validate(attributes)
+	}
+	return nil
+}
+
+func digits(n string) *regexp.Regexp {
+	return regexp.MustCompile(`^[0-9]{` + n + `}$`)
+}
+
+// Default holds the rules listed in the Form3 account documentation.
+// See https://api-docs.form3.tech/api.html#organisation-accounts
+var Default = NewRegistry(
+	Standard{CountryCode: "AT", BankID: Required, BankIDPattern: digits("5"), BankIDCode: "ATBLZ", Bic: Required,
+		AccountNumber: Optional, AccountNumberPattern: digits("11"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "AU", BankID: Optional, BankIDPattern: digits("6"), BankIDCode: "AUBSB", Bic: Required,
+		AccountNumber: Optional, AccountNumberPattern: digits("6,10"), Iban: Forbidden},
+	Standard{CountryCode: "BE", BankID: Required, BankIDPattern: digits("3"), BankIDCode: "BE", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: digits("7"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "CA", BankID: Optional, BankIDPattern: regexp.MustCompile(`^0[0-9]{8}$`), BankIDCode: "CACPA", Bic: Required,
+		AccountNumber: Optional, AccountNumberPattern: digits("7,12"), Iban: Forbidden},
+	Standard{CountryCode: "CH", BankID: Required, BankIDPattern: digits("5"), BankIDCode: "CHBCC", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: digits("12"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "DE", BankID: Required, BankIDPattern: digits("8"), BankIDCode: "DEBLZ", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: digits("7,10"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "ES", BankID: Required, BankIDPattern: digits("8"), BankIDCode: "ESNCC", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: digits("10"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "FR", BankID: Required, BankIDPattern: regexp.MustCompile(`^[0-9]{10}$`), BankIDCode: "FR", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: regexp.MustCompile(`^[0-9A-Z]{11}([0-9]{2})?$`), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "GB", BankID: Required, BankIDPattern: digits("6"), BankIDCode: "GBDSC", Bic: Required,
+		AccountNumber: Optional, AccountNumberPattern: digits("8"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "GR", BankID: Required, BankIDPattern: digits("7"), BankIDCode: "GRBIC", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: digits("16"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "HK", BankID: Optional, BankIDPattern: digits("3"), BankIDCode: "HKNCC", Bic: Required,
+		AccountNumber: Optional, AccountNumberPattern: digits("9,12"), Iban: Forbidden},
+	Standard{CountryCode: "IT", BankID: Required, BankIDPattern: digits("10"), BankIDCode: "ITNCC", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: regexp.MustCompile(`^[0-9A-Z]{12}$`), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "LU", BankID: Required, BankIDPattern: digits("3"), BankIDCode: "LULUX", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: regexp.MustCompile(`^[0-9A-Z]{13}$`), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "NL", BankID: Forbidden, Bic: Required,
+		AccountNumber: Optional, AccountNumberPattern: digits("10"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "PL", BankID: Required, BankIDPattern: digits("8"), BankIDCode: "PLKNR", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: digits("16"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "PT", BankID: Required, BankIDPattern: digits("8"), BankIDCode: "PTNCC", Bic: Optional,
+		AccountNumber: Optional, AccountNumberPattern: digits("11"), Iban: Optional, IbanGenerated: true},
+	Standard{CountryCode: "US", BankID: Required, BankIDPattern: digits("9"), BankIDCode: "USABA", Bic: Required,
+		AccountNumber: Optional, AccountNumberPattern: digits("6,17"), Iban: Forbidden},
+)
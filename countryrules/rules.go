@@ -0,0 +1,128 @@
+package countryrules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/client-library/domain"
+)
+
+// Presence says whether a country expects an attribute.
+type Presence int
+
+const (
+	Optional Presence = iota
+	Required
+	Forbidden
+)
+
+func (p Presence) String() string {
+	switch p {
+	case Required:
+		return "required"
+	case Forbidden:
+		return "forbidden"
+	default:
+		return "optional"
+	}
+}
+
+// Rules validates and normalises the attributes of accounts held in one country.
+type Rules interface {
+	Country() string
+	// Fields reports the presence of the country specific attributes,
+	// keyed by their JSON name.
+	Fields() map[string]Presence
+	Normalise(attributes domain.Attributes) domain.Attributes
+	Validate(attributes domain.Attributes) []domain.FieldError
+}
+
+// Standard covers the rules Form3 applies to most countries: a national bank
+// code of a fixed format, an optional BIC and account number, and an IBAN that
+// is either generated by Form3 or not supported at all.
+type Standard struct {
+	CountryCode          string
+	BankID               Presence
+	BankIDPattern        *regexp.Regexp
+	BankIDCode           string
+	Bic                  Presence
+	AccountNumber        Presence
+	AccountNumberPattern *regexp.Regexp
+	Iban                 Presence
+	// IbanGenerated is set when Form3 generates the IBAN, and the account number
+	// when omitted, for accounts in this country.
+	IbanGenerated bool
+}
+
+func (s Standard) Country() string {
+	return s.CountryCode
+}
+
+func (s Standard) Fields() map[string]Presence {
+	bankIDCode := s.BankID
+	if len(s.BankIDCode) == 0 {
+		bankIDCode = Forbidden
+	}
+
+	return map[string]Presence{
+		"bank_id":        s.BankID,
+		"bank_id_code":   bankIDCode,
+		"bic":            s.Bic,
+		"account_number": s.AccountNumber,
+		"iban":           s.Iban,
+	}
+}
+
+// Normalise upper cases the identifiers, strips the spaces and dashes people
+// type in sort codes and IBANs, and fills in the country's bank_id_code.
+func (s Standard) Normalise(attributes domain.Attributes) domain.Attributes {
+	attributes.BankID = compact(attributes.BankID)
+	attributes.Bic = compact(attributes.Bic)
+	attributes.AccountNumber = compact(attributes.AccountNumber)
+	attributes.Iban = compact(attributes.Iban)
+	attributes.BankIDCode = strings.ToUpper(strings.TrimSpace(attributes.BankIDCode))
+
+	if len(attributes.BankIDCode) == 0 && len(attributes.BankID) > 0 {
+		attributes.BankIDCode = s.BankIDCode
+	}
+
+	return attributes
+}
+
+func (s Standard) Validate(attributes domain.Attributes) []domain.FieldError {
+	var fieldErrors []domain.FieldError
+	check := func(field string, value string, presence Presence, pattern *regexp.Regexp) {
+		switch {
+		case len(value) == 0 && presence == Required:
+			fieldErrors = append(fieldErrors, domain.FieldError{Field: field, Rule: domain.RuleRequired,
+				Message: fmt.Sprintf("%s in body is required for country %s", field, s.CountryCode)})
+		case len(value) > 0 && presence == Forbidden:
+			fieldErrors = append(fieldErrors, domain.FieldError{Field: field, Rule: domain.RuleForbidden, Value: value,
+				Message: fmt.Sprintf("%s in body is not supported for country %s", field, s.CountryCode)})
+		case len(value) > 0 && pattern != nil && !pattern.MatchString(value):
+			fieldErrors = append(fieldErrors, domain.FieldError{Field: field, Rule: domain.RulePattern, Param: pattern.String(), Value: value,
+				Message: fmt.Sprintf("%s in body should match '%s'", field, pattern)})
+		}
+	}
+
+	check("bank_id", attributes.BankID, s.BankID, s.BankIDPattern)
+	check("bic", attributes.Bic, s.Bic, nil)
+	check("account_number", attributes.AccountNumber, s.AccountNumber, s.AccountNumberPattern)
+	check("iban", attributes.Iban, s.Iban, nil)
+
+	switch {
+	case len(attributes.BankIDCode) > 0 && len(s.BankIDCode) == 0:
+		check("bank_id_code", attributes.BankIDCode, Forbidden, nil)
+	case len(attributes.BankIDCode) > 0 && attributes.BankIDCode != s.BankIDCode:
+		fieldErrors = append(fieldErrors, domain.FieldError{Field: "bank_id_code", Rule: domain.RuleEnum, Param: s.BankIDCode, Value: attributes.BankIDCode,
+			Message: fmt.Sprintf("bank_id_code in body should be one of [%s]", s.BankIDCode)})
+	}
+
+	return fieldErrors
+}
+
+func compact(value string) string {
+	value = strings.ToUpper(value)
+	return strings.NewReplacer(" ", "", "-", "").Replace(value)
+}
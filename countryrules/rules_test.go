@@ -0,0 +1,67 @@
+package countryrules
+
+import (
+	"testing"
+
+	"github.com/client-library/domain"
+)
+
+func TestDefault_Validate(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		attributes     domain.Attributes
+		expected_field string
+		expected_rule  string
+	}{
+		{"GBShouldBeValid", domain.Attributes{Country: "GB", BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22"}, "", ""},
+		{"GBBankIDIsRequired", domain.Attributes{Country: "GB", Bic: "NWBKGB22"}, "bank_id", domain.RuleRequired},
+		{"GBBankIDMustHave6Digits", domain.Attributes{Country: "GB", BankID: "4003001", Bic: "NWBKGB22"}, "bank_id", domain.RulePattern},
+		{"GBBicIsRequired", domain.Attributes{Country: "GB", BankID: "400300"}, "bic", domain.RuleRequired},
+		{"GBWrongBankIDCode", domain.Attributes{Country: "GB", BankID: "400300", BankIDCode: "DEBLZ", Bic: "NWBKGB22"}, "bank_id_code", domain.RuleEnum},
+		{"DEShouldBeValid", domain.Attributes{Country: "DE", BankID: "37040044", BankIDCode: "DEBLZ"}, "", ""},
+		{"DEBankIDMustHave8Digits", domain.Attributes{Country: "DE", BankID: "370400"}, "bank_id", domain.RulePattern},
+		{"ATShouldBeValid", domain.Attributes{Country: "AT", BankID: "12000", BankIDCode: "ATBLZ", Bic: "BKAUATWW", AccountNumber: "00123456789"}, "", ""},
+		{"ATBankIDMustHave5Digits", domain.Attributes{Country: "AT", BankID: "120000", Bic: "BKAUATWW"}, "bank_id", domain.RulePattern},
+		{"ATAccountNumberMustHave11Digits", domain.Attributes{Country: "AT", BankID: "12000", Bic: "BKAUATWW", AccountNumber: "123456789"}, "account_number", domain.RulePattern},
+		{"ATBicIsRequired", domain.Attributes{Country: "AT", BankID: "12000"}, "bic", domain.RuleRequired},
+		{"USIbanIsForbidden", domain.Attributes{Country: "US", BankID: "021000021", Bic: "CHASUS33", Iban: "US00123"}, "iban", domain.RuleForbidden},
+		{"NLBankIDCodeIsForbidden", domain.Attributes{Country: "NL", Bic: "ABNANL2A", BankIDCode: "NLBIC"}, "bank_id_code", domain.RuleForbidden},
+		{"UnknownCountryIsLeftToTheAPI", domain.Attributes{Country: "BR"}, "", ""}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fieldErrors := Default.Validate(Default.Normalise(tc.attributes))
+
+			if len(tc.expected_field) == 0 {
+				if len(fieldErrors) > 0 {
+					t.Errorf("Expected no errors, returned %+v", fieldErrors)
+				}
+				return
+			}
+
+			if len(fieldErrors) != 1 || fieldErrors[0].Field != tc.expected_field || fieldErrors[0].Rule != tc.expected_rule {
+				t.Errorf("Expected %s %s, returned %+v", tc.expected_field, tc.expected_rule, fieldErrors)
+			}
+		})
+	}
+}
+
+func TestDefault_Normalise(t *testing.T) {
+	attributes := Default.Normalise(domain.Attributes{Country: "GB", BankID: "40-03-00", Bic: "nwbkgb22", Iban: "gb11 nwbk 4003 0041 4268 19"})
+
+	if attributes.BankID != "400300" || attributes.BankIDCode != "GBDSC" || attributes.Bic != "NWBKGB22" || attributes.Iban != "GB11NWBK40030041426819" {
+		t.Errorf("Unexpected attributes %+v", attributes)
+	}
+}
+
+func TestRegistry_Fields(t *testing.T) {
+	rules, ok := Default.Lookup("NL")
+	if !ok {
+		t.Fatalf("Expected rules for NL")
+	}
+
+	fields := rules.Fields()
+	if fields["bank_id"] != Forbidden || fields["bic"] != Required || fields["account_number"] != Optional {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+}
@@ -0,0 +1,87 @@
+// Package deadline bounds the context of an incoming request by the timeout its
+// caller asked for, so that upstream calls made with that context give up in time.
+package deadline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/client-library/domain"
+)
+
+const (
+	// Header is the HTTP header carrying the timeout, e.g. "X-Request-Timeout: 1.5s".
+	Header = "X-Request-Timeout"
+
+	// MetadataKey is the same timeout in gRPC metadata, whose keys are lower case.
+	MetadataKey = "x-request-timeout"
+)
+
+var ErrInvalid = errors.New("invalid request timeout")
+
+// Parse reads a timeout given as a Go duration ("250ms", "2s") or as a whole
+// number of milliseconds ("250").
+func Parse(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if millis <= 0 {
+			return 0, ErrInvalid
+		}
+		return time.Duration(millis) * time.Millisecond, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, ErrInvalid
+	}
+	return timeout, nil
+}
+
+// WithTimeout bounds ctx by the timeout in value. An empty value leaves ctx
+// without a new deadline; a deadline already on ctx is never extended.
+func WithTimeout(ctx context.Context, value string) (context.Context, context.CancelFunc, error) {
+	if len(strings.TrimSpace(value)) == 0 {
+		return ctx, func() {}, nil
+	}
+
+	timeout, err := Parse(value)
+	if err != nil {
+		return ctx, func() {}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, nil
+}
+
+// FromMetadata bounds ctx by the x-request-timeout entry of gRPC metadata, given
+// as a metadata.MD or any map of lower case keys. The grpc-timeout deadline is
+// already on the context of a gRPC handler.
+func FromMetadata(ctx context.Context, md map[string][]string) (context.Context, context.CancelFunc, error) {
+	values := md[MetadataKey]
+	if len(values) == 0 {
+		return ctx, func() {}, nil
+	}
+	return WithTimeout(ctx, values[0])
+}
+
+// Middleware bounds the request context by the X-Request-Timeout header and
+// rejects a header that cannot be parsed with 400.
+func Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel, err := WithTimeout(r.Context(), r.Header.Get(Header))
+		if err != nil {
+			w.Header().Set("content-type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(domain.CustomException{ErrorMessage: Header + ": " + err.Error()})
+			return
+		}
+		defer cancel()
+
+		next(w, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,100 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	var testCases = []struct {
+		value            string
+		expected_timeout time.Duration
+		expected_error   error
+	}{
+		{"250ms", 250 * time.Millisecond, nil},
+		{"1.5s", 1500 * time.Millisecond, nil},
+		{"250", 250 * time.Millisecond, nil},
+		{" 2s ", 2 * time.Second, nil},
+		{"0", 0, ErrInvalid},
+		{"-1s", 0, ErrInvalid},
+		{"soon", 0, ErrInvalid}}
+
+	for _, tc := range testCases {
+		timeout, err := Parse(tc.value)
+		if !errors.Is(err, tc.expected_error) || timeout != tc.expected_timeout {
+			t.Errorf("%q: expected %v %v, returned %v %v", tc.value, tc.expected_timeout, tc.expected_error, timeout, err)
+		}
+	}
+}
+
+func TestWithTimeout_NeverExtends(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	ctx, cancelTimeout, err := WithTimeout(parent, "1m")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cancelTimeout()
+
+	parentDeadline, _ := parent.Deadline()
+	if deadline, _ := ctx.Deadline(); !deadline.Equal(parentDeadline) {
+		t.Errorf("Expected %v, returned %v", parentDeadline, deadline)
+	}
+}
+
+func TestFromMetadata(t *testing.T) {
+	ctx, cancel, err := FromMetadata(context.Background(), map[string][]string{MetadataKey: {"100ms"}})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > 100*time.Millisecond {
+		t.Errorf("Expected a deadline within 100ms, returned %v", deadline)
+	}
+
+	if ctx, _, _ := FromMetadata(context.Background(), nil); ctx != context.Background() {
+		t.Errorf("Expected the context to be unchanged without metadata")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		header          string
+		expected_status int
+		expected_bound  bool
+	}{
+		{"NoHeader", "", http.StatusOK, false},
+		{"Header", "50ms", http.StatusOK, true},
+		{"InvalidHeader", "soon", http.StatusBadRequest, false}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bound := false
+			handler := Middleware(func(w http.ResponseWriter, r *http.Request) {
+				_, bound = r.Context().Deadline()
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+			if len(tc.header) > 0 {
+				r.Header.Set(Header, tc.header)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tc.expected_status {
+				t.Errorf("Expected %d, returned %d", tc.expected_status, w.Code)
+			}
+			if bound != tc.expected_bound {
+				t.Errorf("Expected deadline %v, returned %v", tc.expected_bound, bound)
+			}
+		})
+	}
+}
@@ -4,15 +4,26 @@ import "time"
 
 //region COMMON MODELS
 
+// Attributes is the full Form3 account attribute set. Optional booleans and
+// enums are pointers, so an unset value is not sent as false or "".
+// See https://api-docs.form3.tech/api.html#organisation-accounts
 type Attributes struct {
-	Country          string   `json:"country,omitempty"`
-	BaseCurrency     string   `json:"base_currency,omitempty"`
-	BankID           string   `json:"bank_id,omitempty"`
-	BankIDCode       string   `json:"bank_id_code,omitempty"`
-	Bic              string   `json:"bic,omitempty"`
-	Name             []string `json:"name,omitempty"`
-	AlternativeNames []string `json:"alternative_names,omitempty"`
-	UserDefinedData  []struct {
+	Country                 string   `json:"country,omitempty"`
+	BaseCurrency            string   `json:"base_currency,omitempty"`
+	BankID                  string   `json:"bank_id,omitempty"`
+	BankIDCode              string   `json:"bank_id_code,omitempty"`
+	Bic                     string   `json:"bic,omitempty"`
+	AccountNumber           string   `json:"account_number,omitempty"`
+	Iban                    string   `json:"iban,omitempty"`
+	Name                    []string `json:"name,omitempty"`
+	AlternativeNames        []string `json:"alternative_names,omitempty"`
+	SecondaryIdentification string   `json:"secondary_identification,omitempty"`
+	AccountClassification   *string  `json:"account_classification,omitempty"`
+	AccountMatchingOptOut   *bool    `json:"account_matching_opt_out,omitempty"`
+	JointAccount            *bool    `json:"joint_account,omitempty"`
+	Status                  *string  `json:"status,omitempty"`
+	Switched                *bool    `json:"switched,omitempty"`
+	UserDefinedData         []struct {
 		Key   string `json:"key,omitempty"`
 		Value string `json:"value,omitempty"`
 	} `json:"user_defined_data,omitempty"`
@@ -21,6 +32,15 @@ type Attributes struct {
 	AcceptanceQualifier string `json:"acceptance_qualifier,omitempty"`
 }
 
+const (
+	AccountClassificationPersonal = "Personal"
+	AccountClassificationBusiness = "Business"
+
+	AccountStatusPending   = "pending"
+	AccountStatusConfirmed = "confirmed"
+	AccountStatusFailed    = "failed"
+)
+
 type Data struct {
 	Attributes     Attributes `json:"attributes,omitempty"`
 	CreatedOn      time.Time  `json:"created_on,omitempty"`
@@ -28,17 +48,40 @@ type Data struct {
 	ModifiedOn     time.Time  `json:"modified_on,omitempty"`
 	OrganisationID string     `json:"organisation_id,omitempty"`
 	Type           string     `json:"type,omitempty"`
-	Version        float64    `json:"version,omitempty"`
+	Version        int64      `json:"version,omitempty"`
 }
 
 type Links struct {
 	First string `json:"first,omitempty"`
 	Last  string `json:"last,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
 	Self  string `json:"self,omitempty"`
 }
 
+// Bank is an entry of the bank directory, identified by its BIC.
+type Bank struct {
+	Bic        string `json:"bic"`
+	Country    string `json:"country"`
+	BankID     string `json:"bank_id,omitempty"`
+	BankIDCode string `json:"bank_id_code,omitempty"`
+	Name       string `json:"name"`
+	Branch     string `json:"branch,omitempty"`
+}
+
 type CustomException struct {
-	ErrorMessage string `json:"error_message"`
+	ErrorMessage string       `json:"error_message"`
+	FieldErrors  []FieldError `json:"field_errors,omitempty"`
+}
+
+// FieldError describes a single attribute that failed validation,
+// e.g. {Field: "country", Rule: "required"}.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
 }
 
 //endregion
@@ -52,7 +95,9 @@ type GetAccountByIdBackendResult struct {
 
 type GetAccountByIdResult struct {
 	CreatedOn  time.Time  `json:"created_on"`
+	Version    int64      `json:"version"`
 	Attributes Attributes `json:"attributes"`
+	Bank       *Bank      `json:"bank,omitempty"`
 }
 
 //endregion
@@ -71,6 +116,8 @@ type CreateAccountResult struct {
 }
 
 type CreateAccountRequest struct {
+	// ID is optional; supplying it makes retried creates idempotent.
+	ID             string     `json:"id,omitempty"`
 	Attributes     Attributes `json:"attributes"`
 	OrganisationID string     `json:"organisation_id"`
 }
@@ -81,6 +128,80 @@ type CreateAccountBackendRequest struct {
 
 //endregion
 
+//region LIST MODELS
+
+type ListAccountsBackendResult struct {
+	Data  []Data `json:"data"`
+	Links Links  `json:"links"`
+}
+
+type AccountResult struct {
+	AccountId      string     `json:"account_id"`
+	OrganisationID string     `json:"organisation_id"`
+	Version        int64      `json:"version"`
+	CreatedOn      time.Time  `json:"created_on"`
+	ModifiedOn     time.Time  `json:"modified_on"`
+	Attributes     Attributes `json:"attributes"`
+}
+
+type ListAccountsResult struct {
+	Accounts []AccountResult `json:"accounts"`
+	Links    Links           `json:"links"`
+}
+
+//endregion
+
+//region UPDATE MODELS
+
+// AttributesPatch holds only the attributes being changed; nil fields are left untouched.
+type AttributesPatch struct {
+	Country                 *string   `json:"country,omitempty"`
+	BaseCurrency            *string   `json:"base_currency,omitempty"`
+	BankID                  *string   `json:"bank_id,omitempty"`
+	BankIDCode              *string   `json:"bank_id_code,omitempty"`
+	Bic                     *string   `json:"bic,omitempty"`
+	AccountNumber           *string   `json:"account_number,omitempty"`
+	Iban                    *string   `json:"iban,omitempty"`
+	Name                    *[]string `json:"name,omitempty"`
+	AlternativeNames        *[]string `json:"alternative_names,omitempty"`
+	SecondaryIdentification *string   `json:"secondary_identification,omitempty"`
+	AccountClassification   *string   `json:"account_classification,omitempty"`
+	AccountMatchingOptOut   *bool     `json:"account_matching_opt_out,omitempty"`
+	JointAccount            *bool     `json:"joint_account,omitempty"`
+	Status                  *string   `json:"status,omitempty"`
+	Switched                *bool     `json:"switched,omitempty"`
+	ValidationType          *string   `json:"validation_type,omitempty"`
+	ReferenceMask           *string   `json:"reference_mask,omitempty"`
+	AcceptanceQualifier     *string   `json:"acceptance_qualifier,omitempty"`
+}
+
+type UpdateAccountBackendRequest struct {
+	Data UpdateData `json:"data"`
+}
+
+type UpdateData struct {
+	Attributes AttributesPatch `json:"attributes"`
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int64           `json:"version"`
+}
+
+type UpdateAccountBackendResult struct {
+	Data  Data `json:"data"`
+	Links `json:"links"`
+}
+
+//endregion
+
+//region VALIDATION MODELS
+
+type AccountValidationResult struct {
+	Valid  bool   `json:"valid"`
+	Source string `json:"source"`
+}
+
+//endregion
+
 //region DELETE MODELS
 
 type DeleteAccountResult struct {
@@ -89,3 +210,18 @@ type DeleteAccountResult struct {
 }
 
 //endregion
+
+//region HEALTH MODELS
+
+const (
+	HealthUp       = "up"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+)
+
+type HealthResult struct {
+	Status         string `json:"status"`
+	CircuitBreaker string `json:"circuit_breaker"`
+}
+
+//endregion
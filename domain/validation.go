@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validation rules reported in FieldError.Rule.
+const (
+	RuleRequired    = "required"
+	RuleType        = "type"
+	RulePattern     = "pattern"
+	RuleMaxLength   = "max_length"
+	RuleMinLength   = "min_length"
+	RuleEnum        = "enum"
+	RuleMaxItems    = "max_items"
+	RuleMinItems    = "min_items"
+	RuleCountryCode = "country_code"
+	RuleCurrency    = "currency"
+	RuleForbidden   = "forbidden"
+	RuleInvalid     = "invalid"
+)
+
+const (
+	maxNameLength       = 140
+	maxNames            = 4
+	maxAlternativeNames = 3
+)
+
+var (
+	uuidPattern          = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	countryPattern       = regexp.MustCompile(`^[A-Z]{2}$`)
+	currencyPattern      = regexp.MustCompile(`^[A-Z]{3}$`)
+	bicPattern           = regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`)
+	bankIDPattern        = regexp.MustCompile(`^[A-Z0-9]{0,16}$`)
+	accountNumberPattern = regexp.MustCompile(`^[A-Z0-9]{0,64}$`)
+	ibanPattern          = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{0,64}$`)
+
+	BankIDCodes            = []string{"GBDSC", "FR", "DEBLZ", "ITNCC", "ESNCC", "CHBCC", "ATBLZ", "BE", "PLKNR", "USABA", "AUBSB", "CACPA", "HKNCC", "GRBIC", "LULUX", "PTNCC"}
+	AccountClassifications = []string{AccountClassificationPersonal, AccountClassificationBusiness}
+	AccountStatuses        = []string{AccountStatusPending, AccountStatusConfirmed, AccountStatusFailed}
+)
+
+// Validate checks the request against the rules enforced by the account API and
+// returns the failures in the same shape the backend reports them. It returns nil
+// when the request is valid.
+func (r CreateAccountRequest) Validate() []FieldError {
+	v := &validator{}
+
+	if len(r.ID) > 0 {
+		v.uuid("id", r.ID)
+	}
+	if v.required("organisation_id", r.OrganisationID) {
+		v.uuid("organisation_id", r.OrganisationID)
+	}
+
+	a := r.Attributes
+	if v.required("country", a.Country) && v.pattern("country", a.Country, countryPattern) {
+		v.member(RuleCountryCode, "country", a.Country, countryCodes, "a valid ISO 3166-1 alpha-2 country code")
+	}
+
+	if len(a.Name) == 0 {
+		v.add(FieldError{Field: "name", Rule: RuleRequired, Message: "name in body is required"})
+	}
+	v.items("name", a.Name, maxNames)
+	v.items("alternative_names", a.AlternativeNames, maxAlternativeNames)
+
+	if len(a.BaseCurrency) > 0 && v.pattern("base_currency", a.BaseCurrency, currencyPattern) {
+		v.member(RuleCurrency, "base_currency", a.BaseCurrency, currencyCodes, "a valid ISO 4217 currency code")
+	}
+	if len(a.Bic) > 0 {
+		v.pattern("bic", a.Bic, bicPattern)
+	}
+	if len(a.BankID) > 0 {
+		v.pattern("bank_id", a.BankID, bankIDPattern)
+	}
+	if len(a.BankIDCode) > 0 {
+		v.enum("bank_id_code", a.BankIDCode, BankIDCodes)
+	}
+	if len(a.AccountNumber) > 0 {
+		v.pattern("account_number", a.AccountNumber, accountNumberPattern)
+	}
+	if len(a.Iban) > 0 {
+		v.pattern("iban", a.Iban, ibanPattern)
+	}
+	v.maxLength("secondary_identification", a.SecondaryIdentification, maxNameLength)
+	if a.AccountClassification != nil {
+		v.enum("account_classification", *a.AccountClassification, AccountClassifications)
+	}
+	if a.Status != nil {
+		v.enum("status", *a.Status, AccountStatuses)
+	}
+
+	return v.errors
+}
+
+// ValidationMessage renders field errors the way the account API does,
+// e.g. "validation failure list:\ncountry in body is required".
+func ValidationMessage(fieldErrors []FieldError) string {
+	lines := []string{"validation failure list:"}
+	for _, fieldError := range fieldErrors {
+		lines = append(lines, fieldError.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) add(fieldError FieldError) {
+	v.errors = append(v.errors, fieldError)
+}
+
+func (v *validator) required(field string, value string) bool {
+	if len(value) == 0 {
+		v.add(FieldError{Field: field, Rule: RuleRequired, Message: field + " in body is required"})
+		return false
+	}
+	return true
+}
+
+func (v *validator) uuid(field string, value string) bool {
+	if !uuidPattern.MatchString(value) {
+		v.add(FieldError{Field: field, Rule: RuleType, Param: "uuid", Value: value,
+			Message: fmt.Sprintf("%s in body must be of type uuid: %q", field, value)})
+		return false
+	}
+	return true
+}
+
+func (v *validator) pattern(field string, value string, pattern *regexp.Regexp) bool {
+	if !pattern.MatchString(value) {
+		v.add(FieldError{Field: field, Rule: RulePattern, Param: pattern.String(), Value: value,
+			Message: fmt.Sprintf("%s in body should match '%s'", field, pattern)})
+		return false
+	}
+	return true
+}
+
+func (v *validator) maxLength(field string, value string, max int) bool {
+	if utf8.RuneCountInString(value) > max {
+		v.add(FieldError{Field: field, Rule: RuleMaxLength, Param: fmt.Sprint(max), Value: value,
+			Message: fmt.Sprintf("%s in body should be at most %d chars long", field, max)})
+		return false
+	}
+	return true
+}
+
+func (v *validator) items(field string, values []string, max int) {
+	if len(values) > max {
+		v.add(FieldError{Field: field, Rule: RuleMaxItems, Param: fmt.Sprint(max),
+			Message: fmt.Sprintf("%s in body should have at most %d items", field, max)})
+	}
+	for i, value := range values {
+		v.maxLength(fmt.Sprintf("%s.%d", field, i), value, maxNameLength)
+	}
+}
+
+func (v *validator) enum(field string, value string, allowed []string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	v.add(FieldError{Field: field, Rule: RuleEnum, Param: strings.Join(allowed, " "), Value: value,
+		Message: fmt.Sprintf("%s in body should be one of [%s]", field, strings.Join(allowed, " "))})
+	return false
+}
+
+func (v *validator) member(rule string, field string, value string, set map[string]bool, description string) bool {
+	if !set[value] {
+		v.add(FieldError{Field: field, Rule: rule, Value: value,
+			Message: fmt.Sprintf("%s in body should be %s: %q", field, description, value)})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+var validRequest = CreateAccountRequest{
+	OrganisationID: "84385b9c-176d-11ed-861d-0242ac120002",
+	Attributes: Attributes{
+		Country:      "GB",
+		BaseCurrency: "GBP",
+		BankID:       "400300",
+		BankIDCode:   "GBDSC",
+		Bic:          "NWBKGB22",
+		Name:         []string{"Fábio Fragoso Kraemer Moraes"},
+	},
+}
+
+func withAttributes(change func(a *Attributes)) CreateAccountRequest {
+	request := validRequest
+	request.Attributes.Name = append([]string{}, validRequest.Attributes.Name...)
+	change(&request.Attributes)
+	return request
+}
+
+func TestCreateAccountRequest_Validate(t *testing.T) {
+	personal, unknown := AccountClassificationPersonal, "Corporate"
+
+	var testCases = []struct {
+		name             string
+		request          CreateAccountRequest
+		expected_field   string
+		expected_rule    string
+		expected_message string
+	}{
+		{"ShouldBeValid", validRequest, "", "", ""},
+		{"ShouldAcceptEnums", withAttributes(func(a *Attributes) { a.AccountClassification = &personal }), "", "", ""},
+		{"InvalidOrganisationID", CreateAccountRequest{OrganisationID: "0d077184-ca1b-4583-a416-29c9a51cf6e", Attributes: validRequest.Attributes},
+			"organisation_id", RuleType, "organisation_id in body must be of type uuid: \"0d077184-ca1b-4583-a416-29c9a51cf6e\""},
+		{"CountryIsRequired", withAttributes(func(a *Attributes) { a.Country = "" }), "country", RuleRequired, "country in body is required"},
+		{"CountryNotMatches", withAttributes(func(a *Attributes) { a.Country = "B" }), "country", RulePattern, "country in body should match"},
+		{"CountryNotISO", withAttributes(func(a *Attributes) { a.Country = "XX" }), "country", RuleCountryCode, "ISO 3166-1"},
+		{"NameIsRequired", withAttributes(func(a *Attributes) { a.Name = nil }), "name", RuleRequired, "name in body is required"},
+		{"NameMoreThan140CharsIsInvalid", withAttributes(func(a *Attributes) { a.Name = []string{strings.Repeat("á", 141)} }),
+			"name.0", RuleMaxLength, "in body should be at most 140 chars long"},
+		{"MoreThan4Names", withAttributes(func(a *Attributes) { a.Name = []string{"a", "b", "c", "d", "e"} }), "name", RuleMaxItems, "should have at most 4 items"},
+		{"InvalidBic", withAttributes(func(a *Attributes) { a.Bic = "NWBK" }), "bic", RulePattern, "bic in body should match"},
+		{"InvalidBankIDCode", withAttributes(func(a *Attributes) { a.BankIDCode = "GBXXX" }), "bank_id_code", RuleEnum, "bank_id_code in body should be one of"},
+		{"InvalidBaseCurrency", withAttributes(func(a *Attributes) { a.BaseCurrency = "GBX" }), "base_currency", RuleCurrency, "ISO 4217"},
+		{"InvalidClassification", withAttributes(func(a *Attributes) { a.AccountClassification = &unknown }), "account_classification", RuleEnum, "should be one of [Personal Business]"}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fieldErrors := tc.request.Validate()
+
+			if len(tc.expected_field) == 0 {
+				if fieldErrors != nil {
+					t.Errorf("Expected no errors, returned %+v", fieldErrors)
+				}
+				return
+			}
+
+			if len(fieldErrors) != 1 {
+				t.Fatalf("Expected 1 error, returned %+v", fieldErrors)
+			}
+			if fieldErrors[0].Field != tc.expected_field || fieldErrors[0].Rule != tc.expected_rule {
+				t.Errorf("Expected %s %s, returned %s %s", tc.expected_field, tc.expected_rule, fieldErrors[0].Field, fieldErrors[0].Rule)
+			}
+			if !strings.Contains(ValidationMessage(fieldErrors), tc.expected_message) {
+				t.Errorf("Expected %s, returned %s", tc.expected_message, fieldErrors[0].Message)
+			}
+		})
+	}
+}
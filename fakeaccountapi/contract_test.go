@@ -0,0 +1,15 @@
+package fakeaccountapi
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/client-library/contracttest"
+)
+
+func TestServer_Contract(t *testing.T) {
+	server := httptest.NewServer(New())
+	t.Cleanup(server.Close)
+
+	contracttest.Suite{BaseURL: server.URL, Patch: true}.Run(t)
+}
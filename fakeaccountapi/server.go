@@ -0,0 +1,401 @@
+// Package fakeaccountapi serves /v1/organisation/accounts from memory with the
+// envelopes, status codes and error texts of the Form3 account API, so tests can
+// run without the docker-compose stack:
+//
+//	server := httptest.NewServer(fakeaccountapi.New())
+//	defer server.Close()
+//	client := accounts.NewClient(accounts.WithBaseURL(server.URL))
+package fakeaccountapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/client-library/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AccountsPath  = "/v1/organisation/accounts"
+	HealthPath    = "/v1/health"
+	AccountType   = "accounts"
+	MaxPageSize   = 100
+	duplicateText = "Account cannot be created as it violates a duplicate constraint"
+)
+
+// Server is an http.Handler holding the accounts in memory. The zero value is
+// not usable; call New.
+type Server struct {
+	mu       sync.Mutex
+	accounts map[string]domain.Data
+	order    []string
+	now      func() time.Time
+}
+
+func New() *Server {
+	return &Server{
+		accounts: map[string]domain.Data{},
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Accounts returns the stored accounts in the order they were created.
+func (s *Server) Accounts() []domain.Data {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]domain.Data, 0, len(s.order))
+	for _, id := range s.order {
+		result = append(result, s.accounts[id])
+	}
+	return result
+}
+
+// Reset deletes every account.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts = map[string]domain.Data{}
+	s.order = nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/vnd.api+json")
+
+	if r.URL.Path == HealthPath && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "up"})
+		return
+	}
+
+	id, ok := accountID(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, "page not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case len(id) == 0 && r.Method == http.MethodGet:
+		s.list(w, r)
+	case len(id) == 0 && r.Method == http.MethodPost:
+		s.create(w, r)
+	case len(id) > 0 && r.Method == http.MethodGet:
+		s.fetch(w, id)
+	case len(id) > 0 && r.Method == http.MethodPatch:
+		s.update(w, r, id)
+	case len(id) > 0 && r.Method == http.MethodDelete:
+		s.delete(w, r, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// accountID splits /v1/organisation/accounts[/id] into the account ID, empty for the collection.
+func accountID(path string) (string, bool) {
+	path = strings.TrimRight(path, "/")
+	if path == AccountsPath {
+		return "", true
+	}
+	id := strings.TrimPrefix(path, AccountsPath+"/")
+	if id == path || strings.Contains(id, "/") {
+		return "", false
+	}
+	return id, true
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var request domain.CreateAccountBackendRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data := request.Data
+	if fieldErrors := validateCreate(data); len(fieldErrors) > 0 {
+		writeError(w, http.StatusBadRequest, domain.ValidationMessage(fieldErrors))
+		return
+	}
+	if _, ok := s.accounts[data.ID]; ok {
+		writeError(w, http.StatusConflict, duplicateText)
+		return
+	}
+
+	data.Type = AccountType
+	data.Version = 0
+	data.CreatedOn = s.now()
+	data.ModifiedOn = data.CreatedOn
+	s.accounts[data.ID] = data
+	s.order = append(s.order, data.ID)
+
+	writeJSON(w, http.StatusCreated, single{Data: resource{data, data.Version}, Links: selfLink(data.ID)})
+}
+
+func (s *Server) fetch(w http.ResponseWriter, id string) {
+	if !validUUID(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+
+	data, ok := s.accounts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, single{Data: resource{data, data.Version}, Links: selfLink(id)})
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, id string) {
+	var request struct {
+		Data struct {
+			ID         string          `json:"id"`
+			Type       string          `json:"type"`
+			Version    *int64          `json:"version"`
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, ok := s.accounts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+	if request.Data.Version == nil {
+		writeError(w, http.StatusBadRequest, domain.ValidationMessage([]domain.FieldError{
+			{Field: "version", Rule: domain.RuleRequired, Message: "version in body is required"}}))
+		return
+	}
+	if *request.Data.Version != data.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	// unmarshalling over the stored attributes only replaces the fields in the patch
+	patched := data
+	patched.Attributes = copyAttributes(data.Attributes)
+	if len(request.Data.Attributes) > 0 {
+		if err := json.Unmarshal(request.Data.Attributes, &patched.Attributes); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if fieldErrors := validateCreate(patched); len(fieldErrors) > 0 {
+		writeError(w, http.StatusBadRequest, domain.ValidationMessage(fieldErrors))
+		return
+	}
+
+	patched.Version++
+	patched.ModifiedOn = s.now()
+	s.accounts[id] = patched
+
+	writeJSON(w, http.StatusOK, single{Data: resource{patched, patched.Version}, Links: selfLink(id)})
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, id string) {
+	if !validUUID(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+
+	data, ok := s.accounts[id]
+	if !ok {
+		// the account API answers an unknown account with an empty 404
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	version, err := strconv.ParseInt(r.URL.Query().Get("version"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+	if version != data.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	delete(s.accounts, id)
+	for i, stored := range s.order {
+		if stored == id {
+			s.order = append(s.order[:i:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	pageSize := MaxPageSize
+	if value := query.Get("page[size]"); len(value) > 0 {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			writeError(w, http.StatusBadRequest, "page[size] must be a positive integer")
+			return
+		}
+		if size < MaxPageSize {
+			pageSize = size
+		}
+	}
+
+	var matches []domain.Data
+	for _, id := range s.order {
+		if data := s.accounts[id]; matchesFilter(data, query) {
+			matches = append(matches, data)
+		}
+	}
+
+	lastPage := 0
+	if len(matches) > 0 {
+		lastPage = (len(matches) - 1) / pageSize
+	}
+
+	pageNumber := 0
+	switch value := query.Get("page[number]"); value {
+	case "", "first":
+	case "last":
+		pageNumber = lastPage
+	default:
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 {
+			writeError(w, http.StatusBadRequest, "page[number] must be a non-negative integer, first or last")
+			return
+		}
+		pageNumber = number
+	}
+
+	result := collection{Data: []resource{}}
+	for i := pageNumber * pageSize; i < len(matches) && i < (pageNumber+1)*pageSize; i++ {
+		result.Data = append(result.Data, resource{matches[i], matches[i].Version})
+	}
+
+	result.Links.Self = pageLink(query, strconv.Itoa(pageNumber))
+	result.Links.First = pageLink(query, "first")
+	result.Links.Last = pageLink(query, "last")
+	if pageNumber < lastPage {
+		result.Links.Next = pageLink(query, strconv.Itoa(pageNumber+1))
+	}
+	if pageNumber > 0 {
+		prev := pageNumber - 1
+		if prev > lastPage {
+			prev = lastPage
+		}
+		result.Links.Prev = pageLink(query, strconv.Itoa(prev))
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// filterAttributes maps the filter[...] names to the account fields they match.
+var filterAttributes = map[string]func(domain.Data) string{
+	"organisation_id": func(d domain.Data) string { return d.OrganisationID },
+	"account_number":  func(d domain.Data) string { return d.Attributes.AccountNumber },
+	"bank_id":         func(d domain.Data) string { return d.Attributes.BankID },
+	"bank_id_code":    func(d domain.Data) string { return d.Attributes.BankIDCode },
+	"country":         func(d domain.Data) string { return d.Attributes.Country },
+	"iban":            func(d domain.Data) string { return d.Attributes.Iban },
+}
+
+// matchesFilter applies every filter[...] parameter; comma separated values match any of them.
+func matchesFilter(data domain.Data, query url.Values) bool {
+	for name, field := range filterAttributes {
+		value := query.Get("filter[" + name + "]")
+		if len(value) == 0 {
+			continue
+		}
+
+		matched := false
+		for _, v := range strings.Split(value, ",") {
+			if strings.TrimSpace(v) == field(data) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// pageLink is a link relative to the host, like the ones the account API returns,
+// keeping the filters and page size of the request.
+func pageLink(query url.Values, pageNumber string) string {
+	link := url.Values{}
+	for key, values := range query {
+		link[key] = values
+	}
+	link.Del("page[number]")
+	if len(pageNumber) > 0 {
+		link.Set("page[number]", pageNumber)
+	}
+
+	if encoded := link.Encode(); len(encoded) > 0 {
+		return AccountsPath + "?" + encoded
+	}
+	return AccountsPath
+}
+
+func selfLink(id string) domain.Links {
+	return domain.Links{Self: AccountsPath + "/" + id}
+}
+
+func validUUID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil && len(id) == 36
+}
+
+// copyAttributes copies the pointer fields too, so a patch cannot change a stored account in place.
+func copyAttributes(attributes domain.Attributes) domain.Attributes {
+	var copied domain.Attributes
+	body, _ := json.Marshal(attributes)
+	json.Unmarshal(body, &copied)
+	return copied
+}
+
+// resource always includes the version, which domain.Data omits when it is 0.
+type resource struct {
+	domain.Data
+	Version int64 `json:"version"`
+}
+
+type single struct {
+	Data  resource     `json:"data"`
+	Links domain.Links `json:"links"`
+}
+
+type collection struct {
+	Data  []resource   `json:"data"`
+	Links domain.Links `json:"links"`
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, result interface{}) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, domain.CustomException{ErrorMessage: message})
+}
@@ -0,0 +1,249 @@
+package fakeaccountapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/client-library/accounts"
+	"github.com/client-library/domain"
+)
+
+var organisationId = "84385b9c-176d-11ed-861d-0242ac120002"
+
+var testAccount = domain.CreateAccountRequest{
+	ID:             "802052e6-182e-11ed-861d-0242ac120002",
+	OrganisationID: organisationId,
+	Attributes:     domain.Attributes{Country: "GB", BankID: "400300", Bic: "NWBKGB22", Name: []string{"Fábio"}},
+}
+
+func startServer(t *testing.T) (*Server, *httptest.Server, *accounts.Client) {
+	fake := New()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server, accounts.NewClient(accounts.WithBaseURL(server.URL), accounts.WithRetryPolicy(accounts.NoRetry))
+}
+
+func TestServer_CreateFetchDelete(t *testing.T) {
+	fake, _, client := startServer(t)
+	ctx := context.Background()
+
+	created, err := client.Create(ctx, testAccount)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if created.AccountId != testAccount.ID {
+		t.Errorf("Expected %s, returned %s", testAccount.ID, created.AccountId)
+	}
+
+	fetched, err := client.Fetch(ctx, testAccount.ID)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if fetched.Version != 0 || fetched.CreatedOn.IsZero() || fetched.Attributes.Country != "GB" {
+		t.Errorf("Unexpected account %+v", fetched)
+	}
+
+	if _, err := client.Delete(ctx, testAccount.ID, 0); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if len(fake.Accounts()) != 0 {
+		t.Errorf("Expected no accounts, returned %d", len(fake.Accounts()))
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	_, server, client := startServer(t)
+	ctx := context.Background()
+	missing := "50078af6-1b5e-11ed-861d-0242ac120002"
+
+	if _, err := client.Create(ctx, testAccount); err != nil {
+		t.Fatalf(err.Error())
+	}
+	// the client resolves a duplicate with the same attributes to the stored account
+	duplicate := testAccount
+	duplicate.Attributes.Name = []string{"Someone Else"}
+
+	var testCases = []struct {
+		name             string
+		call             func() error
+		expected_error   error
+		expected_message string
+	}{
+		{"Duplicate", func() error { _, err := client.Create(ctx, duplicate); return err },
+			accounts.ErrDuplicate, "Account cannot be created as it violates a duplicate constraint"},
+		{"FetchNotFound", func() error { _, err := client.Fetch(ctx, missing); return err },
+			accounts.ErrNotFound, "record " + missing + " does not exist"},
+		{"DeleteNotFound", func() error { _, err := client.Delete(ctx, missing, 0); return err },
+			accounts.ErrNotFound, "Not Found"},
+		{"DeleteInvalidVersion", func() error { _, err := client.Delete(ctx, testAccount.ID, 3); return err },
+			accounts.ErrVersionConflict, "invalid version"},
+		{"UpdateInvalidVersion", func() error {
+			_, err := client.Update(ctx, testAccount.ID, 3, domain.AttributesPatch{})
+			return err
+		}, accounts.ErrVersionConflict, "invalid version"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+
+			var apiErr *accounts.APIError
+			if !errors.As(err, &apiErr) || !errors.Is(err, tc.expected_error) {
+				t.Fatalf("Expected %v, returned %v", tc.expected_error, err)
+			}
+			if apiErr.Message != tc.expected_message {
+				t.Errorf("Expected %s, returned %s", tc.expected_message, apiErr.Message)
+			}
+		})
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		request, err := http.NewRequest(method, server.URL+AccountsPath+"/not-a-uuid?version=0", nil)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		body, _ := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if response.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "id is not a valid uuid") {
+			t.Errorf("Expected %s to answer 400 id is not a valid uuid, returned %d %s", method, response.StatusCode, body)
+		}
+	}
+}
+
+func TestServer_Validation(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		data             domain.Data
+		expected_message string
+	}{
+		{"IDIsRequired", domain.Data{OrganisationID: organisationId, Attributes: testAccount.Attributes},
+			"id in body is required"},
+		{"InvalidOrganisationID", domain.Data{ID: testAccount.ID, OrganisationID: "0d077184-ca1b-4583-a416-29c9a51cf6e", Attributes: testAccount.Attributes},
+			`organisation_id in body must be of type uuid: "0d077184-ca1b-4583-a416-29c9a51cf6e"`},
+		{"InvalidType", domain.Data{ID: testAccount.ID, OrganisationID: organisationId, Type: "acounts", Attributes: testAccount.Attributes},
+			"type in body should be one of [accounts]"},
+		{"CountryIsRequired", domain.Data{ID: testAccount.ID, OrganisationID: organisationId, Attributes: domain.Attributes{Name: []string{"Fábio"}}},
+			"country in body is required"},
+		{"NameIsRequired", domain.Data{ID: testAccount.ID, OrganisationID: organisationId, Attributes: domain.Attributes{Country: "GB"}},
+			"name in body is required"},
+		{"InvalidBankIDCode", domain.Data{ID: testAccount.ID, OrganisationID: organisationId,
+			Attributes: domain.Attributes{Country: "GB", BankIDCode: "gbdsc", Name: []string{"Fábio"}}},
+			"bank_id_code in body should match '^[A-Z]{0,16}$'"},
+		{"TooManyNames", domain.Data{ID: testAccount.ID, OrganisationID: organisationId,
+			Attributes: domain.Attributes{Country: "GB", Name: []string{"A", "B", "C", "D", "E"}}},
+			"name in body should have at most 4 items"},
+	}
+
+	_, server, _ := startServer(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _ := json.Marshal(domain.CreateAccountBackendRequest{Data: tc.data})
+			response, err := http.Post(server.URL+AccountsPath, "application/vnd.api+json", strings.NewReader(string(body)))
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			var exc domain.CustomException
+			json.NewDecoder(response.Body).Decode(&exc)
+			if response.StatusCode != http.StatusBadRequest || !strings.Contains(exc.ErrorMessage, tc.expected_message) {
+				t.Errorf("Expected 400 %s, returned %d %s", tc.expected_message, response.StatusCode, exc.ErrorMessage)
+			}
+			if !strings.HasPrefix(exc.ErrorMessage, "validation failure list:") {
+				t.Errorf("Expected a validation failure list, returned %s", exc.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestServer_Update(t *testing.T) {
+	_, _, client := startServer(t)
+	ctx := context.Background()
+
+	if _, err := client.Create(ctx, testAccount); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	iban := "GB11NWBK40030041426819"
+	updated, err := client.Update(ctx, testAccount.ID, 0, domain.AttributesPatch{Iban: &iban})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if updated.Version != 1 || updated.Attributes.Iban != iban || updated.Attributes.Country != "GB" {
+		t.Errorf("Expected version 1 with the iban and the other attributes kept, returned %+v", updated)
+	}
+}
+
+func TestServer_ListPagination(t *testing.T) {
+	_, server, client := startServer(t)
+	ctx := context.Background()
+
+	ids := []string{
+		"0a0d8a8e-1b5e-11ed-861d-0242ac120002",
+		"1b1d8a8e-1b5e-11ed-861d-0242ac120002",
+		"2c2d8a8e-1b5e-11ed-861d-0242ac120002",
+		"3d3d8a8e-1b5e-11ed-861d-0242ac120002",
+		"4e4d8a8e-1b5e-11ed-861d-0242ac120002",
+	}
+	for i, id := range ids {
+		request := testAccount
+		request.ID = id
+		if i%2 == 1 {
+			request.Attributes.Country = "FR"
+			request.Attributes.BankID = "2004101005"
+			request.Attributes.Bic = "PSSTFRPP"
+		}
+		if _, err := client.Create(ctx, request); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	var listed []string
+	it := client.Iterate(ctx, accounts.ListOptions{PageSize: 2})
+	for it.Next() {
+		listed = append(listed, it.Account().AccountId)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if strings.Join(listed, ",") != strings.Join(ids, ",") {
+		t.Errorf("Expected %v, returned %v", ids, listed)
+	}
+
+	page, err := client.List(ctx, accounts.ListOptions{PageSize: 2, Filter: accounts.AccountFilter{Country: []string{"FR"}}})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if len(page.Accounts) != 2 || len(page.Links.Next) > 0 {
+		t.Errorf("Expected a single page of 2 FR accounts, returned %+v", page)
+	}
+	second, err := client.List(ctx, accounts.ListOptions{PageNumber: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if !strings.Contains(second.Links.Self, "page%5Bnumber%5D=1") {
+		t.Errorf("Expected the self link to keep page 1, returned %s", second.Links.Self)
+	}
+	if !strings.Contains(page.Links.First, "filter%5Bcountry%5D=FR") || !strings.Contains(page.Links.Last, "page%5Bnumber%5D=last") {
+		t.Errorf("Expected links keeping the filter, returned %+v", page.Links)
+	}
+
+	response, err := http.Get(server.URL + AccountsPath + "?page[number]=9")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	body, _ := ioutil.ReadAll(response.Body)
+	if !strings.Contains(string(body), `"data":[]`) {
+		t.Errorf("Expected an empty data array past the last page, returned %s", body)
+	}
+}
@@ -0,0 +1,144 @@
+package fakeaccountapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/client-library/domain"
+)
+
+// The fake keeps its own copy of the account API rules rather than calling
+// domain.CreateAccountRequest.Validate, so it still catches what the client's
+// validator gets wrong. Like the API, it checks the shape of country, currency
+// and bank ID codes, not that they exist.
+var (
+	uuidPattern          = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	countryPattern       = regexp.MustCompile(`^[A-Z]{2}$`)
+	currencyPattern      = regexp.MustCompile(`^[A-Z]{3}$`)
+	bankIDPattern        = regexp.MustCompile(`^[A-Z0-9]{0,16}$`)
+	bankIDCodePattern    = regexp.MustCompile(`^[A-Z]{0,16}$`)
+	bicPattern           = regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`)
+	accountNumberPattern = regexp.MustCompile(`^[A-Z0-9]{0,64}$`)
+	ibanPattern          = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{0,64}$`)
+
+	accountClassifications = []string{"Personal", "Business"}
+	accountStatuses        = []string{"pending", "confirmed", "failed"}
+)
+
+const (
+	maxNameLength       = 140
+	maxNames            = 4
+	maxAlternativeNames = 3
+)
+
+// validateCreate checks the envelope and attributes with the account API rules.
+func validateCreate(data domain.Data) []domain.FieldError {
+	v := &validator{}
+
+	if v.required("id", data.ID) {
+		v.uuid("id", data.ID)
+	}
+	if v.required("organisation_id", data.OrganisationID) {
+		v.uuid("organisation_id", data.OrganisationID)
+	}
+	if len(data.Type) > 0 {
+		v.enum("type", data.Type, []string{AccountType})
+	}
+
+	a := data.Attributes
+	if v.required("country", a.Country) {
+		v.pattern("country", a.Country, countryPattern)
+	}
+	if len(a.Name) == 0 {
+		v.add(domain.FieldError{Field: "name", Rule: domain.RuleRequired, Message: "name in body is required"})
+	}
+	v.items("name", a.Name, maxNames)
+	v.items("alternative_names", a.AlternativeNames, maxAlternativeNames)
+
+	if len(a.BaseCurrency) > 0 {
+		v.pattern("base_currency", a.BaseCurrency, currencyPattern)
+	}
+	if len(a.BankID) > 0 {
+		v.pattern("bank_id", a.BankID, bankIDPattern)
+	}
+	if len(a.BankIDCode) > 0 {
+		v.pattern("bank_id_code", a.BankIDCode, bankIDCodePattern)
+	}
+	if len(a.Bic) > 0 {
+		v.pattern("bic", a.Bic, bicPattern)
+	}
+	if len(a.AccountNumber) > 0 {
+		v.pattern("account_number", a.AccountNumber, accountNumberPattern)
+	}
+	if len(a.Iban) > 0 {
+		v.pattern("iban", a.Iban, ibanPattern)
+	}
+	v.maxLength("secondary_identification", a.SecondaryIdentification, maxNameLength)
+	if a.AccountClassification != nil {
+		v.enum("account_classification", *a.AccountClassification, accountClassifications)
+	}
+	if a.Status != nil {
+		v.enum("status", *a.Status, accountStatuses)
+	}
+
+	return v.errors
+}
+
+type validator struct {
+	errors []domain.FieldError
+}
+
+func (v *validator) add(fieldError domain.FieldError) {
+	v.errors = append(v.errors, fieldError)
+}
+
+func (v *validator) required(field string, value string) bool {
+	if len(value) == 0 {
+		v.add(domain.FieldError{Field: field, Rule: domain.RuleRequired, Message: field + " in body is required"})
+		return false
+	}
+	return true
+}
+
+func (v *validator) uuid(field string, value string) {
+	if !uuidPattern.MatchString(value) {
+		v.add(domain.FieldError{Field: field, Rule: domain.RuleType, Param: "uuid", Value: value,
+			Message: fmt.Sprintf("%s in body must be of type uuid: %q", field, value)})
+	}
+}
+
+func (v *validator) pattern(field string, value string, pattern *regexp.Regexp) {
+	if !pattern.MatchString(value) {
+		v.add(domain.FieldError{Field: field, Rule: domain.RulePattern, Param: pattern.String(), Value: value,
+			Message: fmt.Sprintf("%s in body should match '%s'", field, pattern)})
+	}
+}
+
+func (v *validator) maxLength(field string, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(domain.FieldError{Field: field, Rule: domain.RuleMaxLength, Param: fmt.Sprint(max), Value: value,
+			Message: fmt.Sprintf("%s in body should be at most %d chars long", field, max)})
+	}
+}
+
+func (v *validator) items(field string, values []string, max int) {
+	if len(values) > max {
+		v.add(domain.FieldError{Field: field, Rule: domain.RuleMaxItems, Param: fmt.Sprint(max),
+			Message: fmt.Sprintf("%s in body should have at most %d items", field, max)})
+	}
+	for i, value := range values {
+		v.maxLength(fmt.Sprintf("%s.%d", field, i), value, maxNameLength)
+	}
+}
+
+func (v *validator) enum(field string, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(domain.FieldError{Field: field, Rule: domain.RuleEnum, Param: strings.Join(allowed, " "), Value: value,
+		Message: fmt.Sprintf("%s in body should be one of [%s]", field, strings.Join(allowed, " "))})
+}
@@ -0,0 +1,148 @@
+package fixture
+
+import (
+	"github.com/client-library/domain"
+	"github.com/client-library/iban"
+)
+
+// AccountBuilder builds a create request field by field. Build fills in the
+// fields left unset.
+type AccountBuilder struct {
+	generator *Generator
+	request   domain.CreateAccountRequest
+	noIban    bool
+}
+
+// NewAccount starts a GB account whose unset fields come from the Default generator.
+func NewAccount() *AccountBuilder {
+	return defaultGenerator.NewAccount()
+}
+
+func (b *AccountBuilder) InCountry(country string) *AccountBuilder {
+	b.request.Attributes.Country = country
+	return b
+}
+
+func (b *AccountBuilder) WithID(id string) *AccountBuilder {
+	b.request.ID = id
+	return b
+}
+
+func (b *AccountBuilder) WithOrganisationID(organisationID string) *AccountBuilder {
+	b.request.OrganisationID = organisationID
+	return b
+}
+
+func (b *AccountBuilder) WithName(names ...string) *AccountBuilder {
+	b.request.Attributes.Name = names
+	return b
+}
+
+func (b *AccountBuilder) WithAlternativeNames(names ...string) *AccountBuilder {
+	b.request.Attributes.AlternativeNames = names
+	return b
+}
+
+func (b *AccountBuilder) WithBankID(bankID string) *AccountBuilder {
+	b.request.Attributes.BankID = bankID
+	return b
+}
+
+func (b *AccountBuilder) WithBIC(bic string) *AccountBuilder {
+	b.request.Attributes.Bic = bic
+	return b
+}
+
+func (b *AccountBuilder) WithAccountNumber(accountNumber string) *AccountBuilder {
+	b.request.Attributes.AccountNumber = accountNumber
+	return b
+}
+
+// WithIBAN sets the IBAN as is; it is not checked against the other identifiers.
+func (b *AccountBuilder) WithIBAN(iban string) *AccountBuilder {
+	b.request.Attributes.Iban = iban
+	return b
+}
+
+// WithoutIBAN leaves the IBAN for the API to generate.
+func (b *AccountBuilder) WithoutIBAN() *AccountBuilder {
+	b.noIban = true
+	return b
+}
+
+func (b *AccountBuilder) WithCurrency(currency string) *AccountBuilder {
+	b.request.Attributes.BaseCurrency = currency
+	return b
+}
+
+func (b *AccountBuilder) WithClassification(classification string) *AccountBuilder {
+	b.request.Attributes.AccountClassification = &classification
+	return b
+}
+
+func (b *AccountBuilder) WithStatus(status string) *AccountBuilder {
+	b.request.Attributes.Status = &status
+	return b
+}
+
+// Build returns the request with the unset fields generated. The generator is
+// drawn from the same way whatever was set, so setting a field does not change
+// the others. A country without generation rules only gets IDs and a name.
+func (b *AccountBuilder) Build() domain.CreateAccountRequest {
+	request := b.request
+	attributes := &request.Attributes
+	c, known := countries[attributes.Country]
+
+	g := b.generator
+	g.mu.Lock()
+	id, organisationID, name := g.id(), g.id(), g.name()
+	var bankID, bic, accountNumber string
+	if known {
+		bankID = c.bankIDPrefix + g.digits(c.bankIDLength-len(c.bankIDPrefix))
+		bic = g.pick(c.bics)
+		accountNumber = g.digits(c.accountLength)
+	}
+	g.mu.Unlock()
+
+	if len(request.ID) == 0 {
+		request.ID = id
+	}
+	if len(request.OrganisationID) == 0 {
+		request.OrganisationID = organisationID
+	}
+	if len(attributes.Name) == 0 {
+		attributes.Name = []string{name}
+	}
+	if !known {
+		return request
+	}
+
+	if len(attributes.BankID) == 0 {
+		attributes.BankID = bankID
+	}
+	if len(attributes.BankID) > 0 && len(attributes.BankIDCode) == 0 {
+		attributes.BankIDCode = c.bankIDCode
+	}
+	if len(attributes.Bic) == 0 {
+		attributes.Bic = bic
+	}
+	if len(attributes.AccountNumber) == 0 {
+		attributes.AccountNumber = accountNumber
+	}
+	if len(attributes.BaseCurrency) == 0 {
+		attributes.BaseCurrency = c.currency
+	}
+	if c.iban && !b.noIban && len(attributes.Iban) == 0 {
+		generated, err := iban.Build(iban.Components{
+			Country:       attributes.Country,
+			BankCode:      attributes.Bic,
+			BankID:        attributes.BankID,
+			AccountNumber: attributes.AccountNumber,
+		})
+		if err == nil {
+			attributes.Iban = generated
+		}
+	}
+
+	return request
+}
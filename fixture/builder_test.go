@@ -0,0 +1,73 @@
+package fixture
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/client-library/iban"
+)
+
+func TestAccountBuilder(t *testing.T) {
+	request := NewGenerator(1).NewAccount().InCountry("GB").
+		WithName("Fábio Gonçalves").WithBankID("400300").WithBIC("NWBKGB22").WithAccountNumber("41426819").Build()
+
+	attributes := request.Attributes
+	if attributes.Iban != "GB16NWBK40030041426819" {
+		t.Errorf("Expected GB16NWBK40030041426819, returned %s", attributes.Iban)
+	}
+	if attributes.BankIDCode != "GBDSC" || attributes.BaseCurrency != "GBP" {
+		t.Errorf("Expected GBDSC and GBP, returned %s and %s", attributes.BankIDCode, attributes.BaseCurrency)
+	}
+	if !reflect.DeepEqual(attributes.Name, []string{"Fábio Gonçalves"}) {
+		t.Errorf("Expected [Fábio Gonçalves], returned %v", attributes.Name)
+	}
+	if fieldErrors := request.Validate(); len(fieldErrors) > 0 {
+		t.Errorf("Expected no errors, returned %+v", fieldErrors)
+	}
+}
+
+func TestAccountBuilder_SettingAFieldKeepsTheOthers(t *testing.T) {
+	expected := NewGenerator(3).NewAccount().InCountry("DE").Build()
+	returned := NewGenerator(3).NewAccount().InCountry("DE").WithName("Jürgen Schäfer").Build()
+
+	if returned.ID != expected.ID || returned.Attributes.Iban != expected.Attributes.Iban {
+		t.Errorf("Expected %s %s, returned %s %s", expected.ID, expected.Attributes.Iban, returned.ID, returned.Attributes.Iban)
+	}
+}
+
+func TestAccountBuilder_Iban(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		builder       *AccountBuilder
+		expected_iban bool
+	}{
+		{"Generated", NewGenerator(1).NewAccount().InCountry("NL"), true},
+		{"Without", NewGenerator(1).NewAccount().InCountry("NL").WithoutIBAN(), false},
+		{"NationalCheckDigits", NewGenerator(1).NewAccount().InCountry("ES"), true},
+		{"NoIbanCountry", NewGenerator(1).NewAccount().InCountry("US"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attributes := tc.builder.Build().Attributes
+
+			if (len(attributes.Iban) > 0) != tc.expected_iban {
+				t.Fatalf("Expected iban %t, returned %s", tc.expected_iban, attributes.Iban)
+			}
+			if tc.expected_iban {
+				if err := iban.Validate(attributes.Iban); err != nil || !strings.HasPrefix(attributes.Iban, attributes.Country) {
+					t.Errorf("Expected a valid %s iban, returned %s %v", attributes.Country, attributes.Iban, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAccountBuilder_UnknownCountry(t *testing.T) {
+	request := NewGenerator(1).NewAccount().InCountry("JP").Build()
+
+	if len(request.ID) == 0 || len(request.Attributes.Name) != 1 || len(request.Attributes.BankID) > 0 {
+		t.Errorf("Expected only ids and a name, returned %+v", request)
+	}
+}
@@ -0,0 +1,62 @@
+package fixture
+
+import "sort"
+
+// country describes how to make up identifiers that pass countryrules.Default
+// and, when iban is set, build an IBAN that the iban package accepts.
+type country struct {
+	bankIDCode string
+	// bankIDPrefix is followed by random digits up to bankIDLength.
+	bankIDPrefix  string
+	bankIDLength  int
+	accountLength int
+	currency      string
+	// bics are real BICs of the country, whose first four letters are the
+	// bank code of GB and NL IBANs.
+	bics []string
+	// iban is set for the countries with an IBAN layout; the IBAN of the
+	// others is left to the API.
+	iban bool
+}
+
+var countries = map[string]country{
+	"AT": {bankIDCode: "ATBLZ", bankIDLength: 5, accountLength: 11, currency: "EUR", bics: []string{"BKAUATWW", "RZBAATWW", "GIBAATWW"}, iban: true},
+	"AU": {bankIDCode: "AUBSB", bankIDLength: 6, accountLength: 9, currency: "AUD", bics: []string{"CTBAAU2S", "NATAAU33", "WPACAU2S"}},
+	"BE": {bankIDCode: "BE", bankIDLength: 3, accountLength: 7, currency: "EUR", bics: []string{"GEBABEBB", "KREDBEBB", "BBRUBEBB"}, iban: true},
+	"CA": {bankIDCode: "CACPA", bankIDPrefix: "0", bankIDLength: 9, accountLength: 7, currency: "CAD", bics: []string{"ROYCCAT2", "TDOMCAT2", "BOFMCAM2"}},
+	"CH": {bankIDCode: "CHBCC", bankIDLength: 5, accountLength: 12, currency: "CHF", bics: []string{"UBSWCHZH", "CRESCHZZ", "POFICHBE"}, iban: true},
+	"DE": {bankIDCode: "DEBLZ", bankIDLength: 8, accountLength: 10, currency: "EUR", bics: []string{"DEUTDEFF", "COBADEFF", "GENODEFF"}, iban: true},
+	"ES": {bankIDCode: "ESNCC", bankIDLength: 8, accountLength: 10, currency: "EUR", bics: []string{"BSCHESMM", "CAIXESBB", "BBVAESMM"}, iban: true},
+	"FR": {bankIDCode: "FR", bankIDLength: 10, accountLength: 11, currency: "EUR", bics: []string{"BNPAFRPP", "SOGEFRPP", "PSSTFRPP"}, iban: true},
+	"GB": {bankIDCode: "GBDSC", bankIDLength: 6, accountLength: 8, currency: "GBP", bics: []string{"NWBKGB22", "BARCGB22", "LOYDGB2L", "HBUKGB4B"}, iban: true},
+	"GR": {bankIDCode: "GRBIC", bankIDLength: 7, accountLength: 16, currency: "EUR", bics: []string{"ETHNGRAA", "PIRBGRAA", "EFGBGRAA"}, iban: true},
+	"HK": {bankIDCode: "HKNCC", bankIDLength: 3, accountLength: 9, currency: "HKD", bics: []string{"HSBCHKHH", "BKCHHKHH", "SCBLHKHH"}},
+	"IT": {bankIDCode: "ITNCC", bankIDLength: 10, accountLength: 12, currency: "EUR", bics: []string{"UNCRITMM", "BCITITMM", "BPMOIT22"}, iban: true},
+	"LU": {bankIDCode: "LULUX", bankIDLength: 3, accountLength: 13, currency: "EUR", bics: []string{"BCEELULL", "BGLLLULL", "CCRALULL"}, iban: true},
+	"NL": {accountLength: 10, currency: "EUR", bics: []string{"ABNANL2A", "INGBNL2A", "RABONL2U"}, iban: true},
+	"PL": {bankIDCode: "PLKNR", bankIDLength: 8, accountLength: 16, currency: "PLN", bics: []string{"PKOPPLPW", "BPKOPLPW", "INGBPLPW"}, iban: true},
+	"PT": {bankIDCode: "PTNCC", bankIDLength: 8, accountLength: 11, currency: "EUR", bics: []string{"CGDIPTPL", "BCOMPTPL", "TOTAPTPL"}, iban: true},
+	"US": {bankIDCode: "USABA", bankIDLength: 9, accountLength: 10, currency: "USD", bics: []string{"CHASUS33", "BOFAUS3N", "CITIUS33"}},
+}
+
+// Countries returns the country codes the generator makes accounts for, sorted.
+func Countries() []string {
+	codes := make([]string, 0, len(countries))
+	for code := range countries {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// firstNames and lastNames mix plain ASCII names with ones whose diacritics
+// catch encoding mistakes on the way to the API and back.
+var firstNames = []string{
+	"Fábio", "José", "Zoë", "Chloé", "Björn", "Łukasz", "Søren", "Renée", "Ana", "João",
+	"Élodie", "François", "Jürgen", "Ines", "Mia", "Oliver", "Siobhán", "Dağhan", "Nikolaos", "Ümit",
+}
+
+var lastNames = []string{
+	"Gonçalves", "Müller", "Núñez", "Dvořák", "Kowalski", "Papadopoulos", "Smith", "Lefèvre", "Østergaard", "Rossi",
+	"García", "Janssen", "Wójcik", "Brontë", "O'Brien", "Çelik", "Nguyễn", "Peeters", "Schäfer", "Jones",
+}
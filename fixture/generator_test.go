@@ -0,0 +1,59 @@
+package fixture
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/client-library/accounts"
+	"github.com/client-library/countryrules"
+	"github.com/client-library/fakeaccountapi"
+)
+
+func TestGenerator_AccountsAreValid(t *testing.T) {
+	server := httptest.NewServer(fakeaccountapi.New())
+	t.Cleanup(server.Close)
+	client := accounts.NewClient(accounts.WithBaseURL(server.URL), accounts.WithRetryPolicy(accounts.NoRetry))
+
+	g := NewGenerator(42)
+	for _, country := range Countries() {
+		t.Run(country, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				request := g.AccountIn(country)
+
+				if fieldErrors := request.Validate(); len(fieldErrors) > 0 {
+					t.Fatalf("Expected no errors for %+v, returned %+v", request.Attributes, fieldErrors)
+				}
+				if fieldErrors := countryrules.Default.Validate(request.Attributes); len(fieldErrors) > 0 {
+					t.Fatalf("Expected no country errors for %+v, returned %+v", request.Attributes, fieldErrors)
+				}
+				if countries[country].iban && len(request.Attributes.Iban) == 0 {
+					t.Errorf("Expected an iban for %+v", request.Attributes)
+				}
+
+				created, err := client.Create(context.Background(), request)
+				if err != nil {
+					t.Fatalf("Expected %+v to be created, returned %v", request.Attributes, err)
+				}
+				if created.Attributes.Iban != request.Attributes.Iban {
+					t.Errorf("Expected %s, returned %s", request.Attributes.Iban, created.Attributes.Iban)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Reproducible(t *testing.T) {
+	a, b := NewGenerator(7), NewGenerator(7)
+	for i := 0; i < 10; i++ {
+		expected, returned := a.Account(), b.Account()
+		if !reflect.DeepEqual(expected, returned) {
+			t.Fatalf("Expected %+v, returned %+v", expected, returned)
+		}
+	}
+
+	if NewGenerator(7).ID() == NewGenerator(8).ID() {
+		t.Errorf("Expected different seeds to give different ids")
+	}
+}
@@ -0,0 +1,94 @@
+package fixture
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/client-library/accounts"
+	"github.com/client-library/domain"
+
+	"github.com/google/uuid"
+)
+
+// Registry tracks the accounts a test creates and deletes them, at whatever
+// version they have reached, when the test and its subtests finish, even if
+// the test failed midway:
+//
+//	registry := fixture.NewRegistry(t, client)
+//	created, err := registry.Create(ctx, registry.NewAccount().Build())
+//
+// Its IDs are random rather than drawn from a seeded generator, so parallel
+// tests, -count=N runs and accounts leaked by a killed run never collide.
+type Registry struct {
+	t      testing.TB
+	client *accounts.Client
+
+	mu  sync.Mutex
+	ids []string
+}
+
+// NewRegistry returns a registry deleting through client in t.Cleanup.
+func NewRegistry(t testing.TB, client *accounts.Client) *Registry {
+	r := &Registry{t: t, client: client}
+	t.Cleanup(r.cleanup)
+	return r
+}
+
+// ID returns a new account ID, registered before anything is sent so that an
+// account whose create response was lost is deleted too.
+func (r *Registry) ID() string {
+	id := uuid.NewString()
+	r.Register(id)
+	return id
+}
+
+// Register adds an account created some other way, e.g. through the gateway
+// handlers or with an ID the API generated.
+func (r *Registry) Register(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, registered := range r.ids {
+		if registered == id {
+			return
+		}
+	}
+	r.ids = append(r.ids, id)
+}
+
+// IDs returns the registered IDs in registration order.
+func (r *Registry) IDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.ids...)
+}
+
+// NewAccount starts an AccountBuilder from the Default generator with a registered ID.
+func (r *Registry) NewAccount() *AccountBuilder {
+	return NewAccount().WithID(r.ID())
+}
+
+// Create creates the account through the client, giving it a registered ID
+// when it has none.
+func (r *Registry) Create(ctx context.Context, request domain.CreateAccountRequest) (*domain.CreateAccountResult, error) {
+	if len(request.ID) == 0 {
+		request.ID = r.ID()
+	} else {
+		r.Register(request.ID)
+	}
+	return r.client.Create(ctx, request)
+}
+
+// cleanup deletes the accounts newest first. Accounts the test deleted itself,
+// or never managed to create, are skipped.
+func (r *Registry) cleanup() {
+	ids := r.IDs()
+	for i := len(ids) - 1; i >= 0; i-- {
+		_, err := r.client.DeleteLatest(context.Background(), ids[i])
+		if err != nil && !errors.Is(err, accounts.ErrNotFound) {
+			r.t.Errorf("Cleanup: account %s was not deleted: %v", ids[i], err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package fixture
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/client-library/accounts"
+	"github.com/client-library/domain"
+	"github.com/client-library/fakeaccountapi"
+)
+
+func TestRegistry_DeletesAccountsOnCleanup(t *testing.T) {
+	fake := fakeaccountapi.New()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client := accounts.NewClient(accounts.WithBaseURL(server.URL), accounts.WithRetryPolicy(accounts.NoRetry))
+	ctx := context.Background()
+
+	t.Run("Test", func(t *testing.T) {
+		registry := NewRegistry(t, client)
+
+		created, err := registry.Create(ctx, registry.NewAccount().Build())
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		name := "Renée Brontë"
+		if _, err := client.Update(ctx, created.AccountId, 0, domain.AttributesPatch{Name: &[]string{name}}); err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		// created without an ID, then deleted by the test itself
+		deleted, err := registry.Create(ctx, NewAccount().InCountry("FR").Build())
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		if _, err := client.Delete(ctx, deleted.AccountId, 0); err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		// registered but never created
+		registry.ID()
+
+		if len(registry.IDs()) != 3 || len(fake.Accounts()) != 1 {
+			t.Errorf("Expected 3 ids and 1 account, returned %v and %d", registry.IDs(), len(fake.Accounts()))
+		}
+	})
+
+	if len(fake.Accounts()) != 0 {
+		t.Errorf("Expected no accounts after cleanup, returned %d", len(fake.Accounts()))
+	}
+}
+
+func TestRegistry_UniqueIDs(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		t.Run("Run", func(t *testing.T) {
+			registry := &Registry{t: t}
+			id := registry.ID()
+			if seen[id] {
+				t.Errorf("Expected unique ids, returned %s twice", id)
+			}
+			seen[id] = true
+			registry.Register(id)
+			if len(registry.IDs()) != 1 {
+				t.Errorf("Expected 1 id, returned %v", registry.IDs())
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package iban
+
+import "regexp"
+
+// layout describes the BBAN of a country: an optional bank code taken from the
+// BIC, the Form3 bank_id, the account number and the national check digits,
+// each of a fixed length. The national check digits are kept apart so that
+// bank_id and account_number have the lengths the country rules expect.
+type layout struct {
+	bankCode      int
+	bankID        int
+	accountNumber int
+	check         int
+	checkAt       checkPosition
+	// nationalCheck computes the national check digits from the bank ID and
+	// account number; set for every layout with check digits.
+	nationalCheck func(bankID string, accountNumber string) string
+	// structure is the BBAN as a regular expression.
+	structure string
+	// padAccount left pads shorter account numbers with zeros.
+	padAccount bool
+	compiled   *regexp.Regexp
+}
+
+// checkPosition tells where the national check digits sit in the BBAN.
+type checkPosition int
+
+const (
+	checkAfterAccount checkPosition = iota
+	checkBeforeBankID
+	checkAfterBankID
+)
+
+var layouts = map[string]*layout{
+	"AT": {bankID: 5, accountNumber: 11, structure: `[0-9]{16}`, padAccount: true},
+	"BE": {bankID: 3, accountNumber: 7, check: 2, nationalCheck: belgianCheck, structure: `[0-9]{12}`},
+	"CH": {bankID: 5, accountNumber: 12, structure: `[0-9]{5}[A-Z0-9]{12}`},
+	"DE": {bankID: 8, accountNumber: 10, structure: `[0-9]{18}`, padAccount: true},
+	"ES": {bankID: 8, accountNumber: 10, check: 2, checkAt: checkAfterBankID, nationalCheck: spanishCheck, structure: `[0-9]{20}`},
+	"FR": {bankID: 10, accountNumber: 11, check: 2, nationalCheck: ribKey, structure: `[0-9]{10}[A-Z0-9]{11}[0-9]{2}`},
+	"GB": {bankCode: 4, bankID: 6, accountNumber: 8, structure: `[A-Z]{4}[0-9]{14}`},
+	"GR": {bankID: 7, accountNumber: 16, structure: `[0-9]{7}[A-Z0-9]{16}`},
+	"IE": {bankCode: 4, bankID: 6, accountNumber: 8, structure: `[A-Z]{4}[0-9]{14}`},
+	"IT": {bankID: 10, accountNumber: 12, check: 1, checkAt: checkBeforeBankID, nationalCheck: italianCIN, structure: `[A-Z][0-9]{10}[A-Z0-9]{12}`},
+	"LU": {bankID: 3, accountNumber: 13, structure: `[0-9]{3}[A-Z0-9]{13}`},
+	"NL": {bankCode: 4, accountNumber: 10, structure: `[A-Z]{4}[0-9]{10}`, padAccount: true},
+	"PL": {bankID: 8, accountNumber: 16, structure: `[0-9]{24}`},
+	"PT": {bankID: 8, accountNumber: 11, check: 2, nationalCheck: portugueseCheck, structure: `[0-9]{21}`},
+}
+
+func init() {
+	for _, l := range layouts {
+		l.compiled = regexp.MustCompile(`^` + l.structure + `$`)
+	}
+}
+
+// lengths holds the IBAN length of every country in the SWIFT IBAN registry.
+var lengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27,
+	"GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24,
+	"ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24, "SC": 31,
+	"SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24,
+	"TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
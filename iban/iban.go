@@ -0,0 +1,173 @@
+// Package iban validates, builds and parses International Bank Account Numbers.
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrCountry  = errors.New("iban: unsupported country")
+	ErrLength   = errors.New("iban: invalid length")
+	ErrFormat   = errors.New("iban: invalid format")
+	ErrChecksum = errors.New("iban: invalid check digits")
+	// ErrBankCode is returned by Build when the country's IBAN starts with
+	// a bank code and none was given.
+	ErrBankCode = errors.New("iban: bank code required")
+)
+
+// Components are the parts of an IBAN in Form3 terms: BankID and AccountNumber
+// match the account attributes of the same name. BankCode is only used by the
+// countries whose IBAN starts with the bank's BIC code, such as GB and NL.
+// NationalCheck holds the national check digits of countries such as ES and
+// IT, or the CIN letter, which are part of neither.
+type Components struct {
+	Country       string
+	CheckDigits   string
+	BankCode      string
+	BankID        string
+	AccountNumber string
+	NationalCheck string
+}
+
+var ibanPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]+$`)
+
+// Normalise upper cases the IBAN and drops the spaces of its printed form.
+func Normalise(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(iban), " ", ""))
+}
+
+// Validate checks the structure, the length of the country and the check digits.
+// The BBAN structure is only checked for the countries with a known layout.
+func Validate(iban string) error {
+	iban = Normalise(iban)
+	if !ibanPattern.MatchString(iban) {
+		return ErrFormat
+	}
+
+	length, ok := lengths[iban[:2]]
+	if !ok {
+		return fmt.Errorf("%w %s", ErrCountry, iban[:2])
+	}
+	if len(iban) != length {
+		return fmt.Errorf("%w: %s should have %d characters", ErrLength, iban[:2], length)
+	}
+
+	if l, ok := layouts[iban[:2]]; ok && !l.compiled.MatchString(iban[4:]) {
+		return fmt.Errorf("%w: %s does not match the %s layout", ErrFormat, iban, iban[:2])
+	}
+
+	if mod97(iban[4:]+iban[:4]) != 1 {
+		return ErrChecksum
+	}
+
+	return nil
+}
+
+// Build assembles the IBAN of an account, computing its check digits. The
+// national check digits are computed too when they are not given; an account
+// number followed by them, as French accounts are often written, is also accepted.
+func Build(c Components) (string, error) {
+	l, ok := layouts[c.Country]
+	if !ok {
+		return "", fmt.Errorf("%w %s", ErrCountry, c.Country)
+	}
+
+	accountNumber := c.AccountNumber
+	national := c.NationalCheck
+	if l.check > 0 && l.checkAt == checkAfterAccount && len(national) == 0 && len(accountNumber) == l.accountNumber+l.check {
+		accountNumber, national = accountNumber[:l.accountNumber], accountNumber[l.accountNumber:]
+	}
+	if l.padAccount && len(accountNumber) < l.accountNumber {
+		accountNumber = strings.Repeat("0", l.accountNumber-len(accountNumber)) + accountNumber
+	}
+
+	bankCode := c.BankCode
+	if l.bankCode > 0 && len(bankCode) == 0 {
+		return "", fmt.Errorf("%w for %s", ErrBankCode, c.Country)
+	}
+	if len(bankCode) > l.bankCode {
+		bankCode = bankCode[:l.bankCode]
+	}
+
+	if len(bankCode) != l.bankCode || len(c.BankID) != l.bankID || len(accountNumber) != l.accountNumber {
+		return "", fmt.Errorf("%w: %s takes a %d character bank id and a %d character account number",
+			ErrFormat, c.Country, l.bankID, l.accountNumber)
+	}
+	if l.check > 0 && len(national) == 0 {
+		national = l.nationalCheck(c.BankID, strings.ToUpper(accountNumber))
+	}
+	if len(national) != l.check {
+		return "", fmt.Errorf("%w: %s takes %d national check characters", ErrFormat, c.Country, l.check)
+	}
+
+	var bban string
+	switch l.checkAt {
+	case checkBeforeBankID:
+		bban = bankCode + national + c.BankID + accountNumber
+	case checkAfterBankID:
+		bban = bankCode + c.BankID + national + accountNumber
+	default:
+		bban = bankCode + c.BankID + accountNumber + national
+	}
+	bban = strings.ToUpper(bban)
+	if !l.compiled.MatchString(bban) {
+		return "", fmt.Errorf("%w: bank code, bank id and account number do not match the %s layout", ErrFormat, c.Country)
+	}
+
+	check := 98 - mod97(bban+c.Country+"00")
+	return fmt.Sprintf("%s%02d%s", c.Country, check, bban), nil
+}
+
+// Parse validates the IBAN and splits it into its components.
+func Parse(iban string) (Components, error) {
+	iban = Normalise(iban)
+	if err := Validate(iban); err != nil {
+		return Components{}, err
+	}
+
+	l, ok := layouts[iban[:2]]
+	if !ok {
+		return Components{}, fmt.Errorf("%w %s", ErrCountry, iban[:2])
+	}
+
+	bban := iban[4:]
+	components := Components{Country: iban[:2], CheckDigits: iban[2:4], BankCode: bban[:l.bankCode]}
+	bban = bban[l.bankCode:]
+
+	switch l.checkAt {
+	case checkBeforeBankID:
+		components.NationalCheck, bban = bban[:l.check], bban[l.check:]
+		components.BankID, components.AccountNumber = bban[:l.bankID], bban[l.bankID:]
+	case checkAfterBankID:
+		components.BankID = bban[:l.bankID]
+		components.NationalCheck = bban[l.bankID : l.bankID+l.check]
+		components.AccountNumber = bban[l.bankID+l.check:]
+	default:
+		components.BankID = bban[:l.bankID]
+		components.AccountNumber = bban[l.bankID : l.bankID+l.accountNumber]
+		components.NationalCheck = bban[l.bankID+l.accountNumber:]
+	}
+	return components, nil
+}
+
+// mod97 computes the ISO 7064 MOD 97-10 remainder, with letters counting as 10 to 35.
+func mod97(value string) int64 {
+	var digits strings.Builder
+	for _, r := range value {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(fmt.Sprint(r - 'A' + 10))
+			continue
+		}
+		digits.WriteRune(r)
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return -1
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64()
+}
@@ -0,0 +1,122 @@
+package iban
+
+import (
+	"errors"
+	"testing"
+)
+
+var testCasesValidate = []struct {
+	name           string
+	iban           string
+	expected_error error
+}{
+	{"GB", "GB29 NWBK 6016 1331 9268 19", nil},
+	{"DE", "DE89370400440532013000", nil},
+	{"FR", "FR1420041010050500013M02606", nil},
+	{"ES", "ES9121000418450200051332", nil},
+	{"NL", "NL91ABNA0417164300", nil},
+	{"NoLayoutOnlyLength", "NO9386011117947", nil},
+	{"LowerCase", "gb29nwbk60161331926819", nil},
+	{"WrongCheckDigits", "GB28NWBK60161331926819", ErrChecksum},
+	{"WrongLength", "GB29NWBK6016133192681", ErrLength},
+	{"UnknownCountry", "XX29NWBK60161331926819", ErrCountry},
+	{"WrongLayout", "GB29NWBK60161331926A19", ErrFormat},
+	{"NotAnIBAN", "400300", ErrFormat}}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range testCasesValidate {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := Validate(tc.iban); !errors.Is(err, tc.expected_error) {
+				t.Errorf("Expected %v, returned %v", tc.expected_error, err)
+			}
+		})
+	}
+}
+
+var testCasesBuild = []struct {
+	name       string
+	components Components
+	expected   string
+}{
+	{"GB", Components{Country: "GB", BankCode: "NWBKGB22", BankID: "601613", AccountNumber: "31926819"}, "GB29NWBK60161331926819"},
+	{"DEPadsAccountNumber", Components{Country: "DE", BankID: "37040044", AccountNumber: "532013000"}, "DE89370400440532013000"},
+	{"FR", Components{Country: "FR", BankID: "2004101005", AccountNumber: "0500013M026" + "06"}, "FR1420041010050500013M02606"},
+	{"FRWithoutKey", Components{Country: "FR", BankID: "2004101005", AccountNumber: "0500013M026"}, "FR1420041010050500013M02606"},
+	{"ES", Components{Country: "ES", BankID: "21000418", AccountNumber: "0200051332"}, "ES9121000418450200051332"},
+	{"BE", Components{Country: "BE", BankID: "539", AccountNumber: "0075470"}, "BE68539007547034"},
+	{"PT", Components{Country: "PT", BankID: "00020123", AccountNumber: "12345678901"}, "PT50000201231234567890154"},
+	{"IT", Components{Country: "IT", BankID: "0542811101", AccountNumber: "000000123456"}, "IT60X0542811101000000123456"},
+	{"NL", Components{Country: "NL", BankCode: "ABNA", AccountNumber: "0417164300"}, "NL91ABNA0417164300"}}
+
+func TestBuildAndParse(t *testing.T) {
+	for _, tc := range testCasesBuild {
+		t.Run(tc.name, func(t *testing.T) {
+			iban, err := Build(tc.components)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			if iban != tc.expected {
+				t.Fatalf("Expected %s, returned %s", tc.expected, iban)
+			}
+
+			components, err := Parse(iban)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			if components.Country != tc.components.Country || components.BankID != tc.components.BankID || components.CheckDigits != iban[2:4] {
+				t.Errorf("Unexpected components %+v", components)
+			}
+		})
+	}
+}
+
+func TestBuild_InvalidComponents(t *testing.T) {
+	if _, err := Build(Components{Country: "GB", BankCode: "NWBK", BankID: "6016", AccountNumber: "31926819"}); !errors.Is(err, ErrFormat) {
+		t.Errorf("Expected %v, returned %v", ErrFormat, err)
+	}
+	if _, err := Build(Components{Country: "GB", BankID: "601613", AccountNumber: "31926819"}); !errors.Is(err, ErrBankCode) {
+		t.Errorf("Expected %v, returned %v", ErrBankCode, err)
+	}
+	if _, err := Build(Components{Country: "US", BankID: "021000021", AccountNumber: "123456"}); !errors.Is(err, ErrCountry) {
+		t.Errorf("Expected %v, returned %v", ErrCountry, err)
+	}
+}
+
+var testCasesParse = []struct {
+	name     string
+	iban     string
+	expected Components
+}{
+	{"ES", "ES9121000418450200051332", Components{Country: "ES", CheckDigits: "91", BankID: "21000418", NationalCheck: "45", AccountNumber: "0200051332"}},
+	{"BE", "BE68539007547034", Components{Country: "BE", CheckDigits: "68", BankID: "539", AccountNumber: "0075470", NationalCheck: "34"}},
+	{"PT", "PT50000201231234567890154", Components{Country: "PT", CheckDigits: "50", BankID: "00020123", AccountNumber: "12345678901", NationalCheck: "54"}},
+	{"IT", "IT60X0542811101000000123456", Components{Country: "IT", CheckDigits: "60", NationalCheck: "X", BankID: "0542811101", AccountNumber: "000000123456"}},
+	{"FR", "FR1420041010050500013M02606", Components{Country: "FR", CheckDigits: "14", BankID: "2004101005", AccountNumber: "0500013M026", NationalCheck: "06"}},
+	{"GB", "GB29NWBK60161331926819", Components{Country: "GB", CheckDigits: "29", BankCode: "NWBK", BankID: "601613", AccountNumber: "31926819"}}}
+
+func TestParse_NationalCheck(t *testing.T) {
+	for _, tc := range testCasesParse {
+		t.Run(tc.name, func(t *testing.T) {
+			components, err := Parse(tc.iban)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			if components != tc.expected {
+				t.Errorf("Expected %+v, returned %+v", tc.expected, components)
+			}
+		})
+	}
+}
+
+func TestBuild_WrongNationalCheck(t *testing.T) {
+	iban, err := Build(Components{Country: "ES", BankID: "21000418", AccountNumber: "0200051332", NationalCheck: "46"})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if iban == "ES9121000418450200051332" {
+		t.Errorf("Expected the given national check digits to be used, returned %s", iban)
+	}
+	if _, err := Build(Components{Country: "ES", BankID: "21000418", AccountNumber: "450200051332"}); !errors.Is(err, ErrFormat) {
+		t.Errorf("Expected %v, returned %v", ErrFormat, err)
+	}
+}
@@ -0,0 +1,92 @@
+package iban
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// belgianCheck is the bank ID and account number modulo 97, 97 for 0.
+func belgianCheck(bankID string, accountNumber string) string {
+	check := mod97(bankID + accountNumber)
+	if check == 0 {
+		check = 97
+	}
+	return fmt.Sprintf("%02d", check)
+}
+
+// spanishCheck is the "dígito de control" pair: one digit over the bank and
+// branch, one over the account number.
+func spanishCheck(bankID string, accountNumber string) string {
+	return spanishDigit("00"+bankID) + spanishDigit(accountNumber)
+}
+
+var spanishWeights = []int{1, 2, 4, 8, 5, 10, 9, 7, 3, 6}
+
+func spanishDigit(value string) string {
+	sum := 0
+	for i, r := range value {
+		sum += int(r-'0') * spanishWeights[i%len(spanishWeights)]
+	}
+
+	digit := 11 - sum%11
+	switch digit {
+	case 11:
+		digit = 0
+	case 10:
+		digit = 1
+	}
+	return fmt.Sprint(digit)
+}
+
+// portugueseCheck is the NIB check: 98 minus the bank ID and account number,
+// followed by 00, modulo 97.
+func portugueseCheck(bankID string, accountNumber string) string {
+	return fmt.Sprintf("%02d", 98-mod97(bankID+accountNumber+"00"))
+}
+
+// ribKey is the French "clé RIB" over the bank code, branch code and account
+// number, whose letters count as digits: A-I and J-R as 1-9, S-Z as 2-9.
+func ribKey(bankID string, accountNumber string) string {
+	account := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'I':
+			return '1' + (r - 'A')
+		case r >= 'J' && r <= 'R':
+			return '1' + (r - 'J')
+		case r >= 'S' && r <= 'Z':
+			return '2' + (r - 'S')
+		}
+		return r
+	}, strings.ToUpper(accountNumber))
+
+	bank, _ := strconv.ParseInt(bankID[:5], 10, 64)
+	branch, _ := strconv.ParseInt(bankID[5:], 10, 64)
+	number, _ := strconv.ParseInt(account, 10, 64)
+	return fmt.Sprintf("%02d", 97-(89*bank+15*branch+3*number)%97)
+}
+
+// italianCIN is the control letter computed over the ABI, CAB and account
+// number, with different values for the characters in odd and even positions.
+func italianCIN(bankID string, accountNumber string) string {
+	sum := 0
+	for i, r := range strings.ToUpper(bankID + accountNumber) {
+		value := 0
+		if r >= 'A' && r <= 'Z' {
+			value = int(r - 'A')
+		} else {
+			value = int(r - '0')
+		}
+
+		if i%2 == 0 {
+			sum += cinOdd[value]
+		} else {
+			sum += value
+		}
+	}
+	return string(rune('A' + sum%26))
+}
+
+// cinOdd holds the values of the characters in odd positions, indexed by the
+// digit, or by the letter from A = 0.
+var cinOdd = []int{1, 0, 5, 7, 9, 13, 15, 17, 19, 21, 2, 4, 18, 20, 11, 3, 6, 8, 12, 14, 16, 10, 22, 25, 24, 23}
@@ -0,0 +1,154 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/client-library/domain"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+)
+
+// statusClientClosedRequest is what the gateway answers a request whose
+// client went away, e.g. a create cancelled halfway.
+const statusClientClosedRequest = 499
+
+// transientStatus holds the client errors that say "try again later" rather
+// than answer the request, such as an upstream rate limit or a cancelled request.
+var transientStatus = map[int]bool{
+	http.StatusRequestTimeout:  true,
+	http.StatusTooEarly:        true,
+	http.StatusTooManyRequests: true,
+	statusClientClosedRequest:  true,
+}
+
+// Middleware stores the first response to each Idempotency-Key of PUT and POST
+// requests and replays it for retries with an identical body. A retry with a
+// different body is rejected with 422. Server errors and transient statuses are
+// not stored, so the request can be retried.
+func Middleware(store Store, next http.HandlerFunc) http.HandlerFunc {
+	var locks keyLocks
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if len(key) == 0 || (r.Method != http.MethodPut && r.Method != http.MethodPost) {
+			next(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeException(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(append([]byte(r.Method+" "+r.URL.Path+"\n"), body...))
+		requestHash := hex.EncodeToString(sum[:])
+
+		// one request per key at a time, so a retry racing the first attempt
+		// waits for its response instead of running twice
+		unlock := locks.lock(key)
+		defer unlock()
+
+		record, ok, err := store.Get(key)
+		if err != nil {
+			writeException(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if ok {
+			if record.RequestHash != requestHash {
+				writeException(w, http.StatusUnprocessableEntity, "Idempotency-Key "+key+" was already used with a different request")
+				return
+			}
+			if len(record.ContentType) > 0 {
+				w.Header().Set("content-type", record.ContentType)
+			}
+			w.Header().Set(HeaderReplayed, "true")
+			w.WriteHeader(record.StatusCode)
+			w.Write(record.Body)
+			return
+		}
+
+		recorder := &recorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next(recorder, r)
+
+		if recorder.statusCode < 500 && !transientStatus[recorder.statusCode] {
+			store.Put(key, Record{
+				RequestHash: requestHash,
+				StatusCode:  recorder.statusCode,
+				ContentType: w.Header().Get("content-type"),
+				Body:        recorder.body.Bytes(),
+				CreatedAt:   time.Now(),
+			})
+		}
+	}
+}
+
+// recorder passes the response through while keeping a copy of it.
+type recorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	waiters int
+}
+
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*keyLock{}
+	}
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		l.locks[key] = lock
+	}
+	lock.waiters++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.mu.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+func writeException(w http.ResponseWriter, statusCode int, message string) {
+	jsonBytes, _ := json.Marshal(domain.CustomException{ErrorMessage: message})
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(jsonBytes)
+}
@@ -0,0 +1,188 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func countingHandler(calls *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `}`))
+	}
+}
+
+func send(handler http.HandlerFunc, key string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPut, "/accounts", strings.NewReader(body))
+	if len(key) > 0 {
+		r.Header.Set(HeaderKey, key)
+	}
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for name, store := range map[string]Store{"Memory": NewMemoryStore(time.Hour), "File": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+			handler := Middleware(store, countingHandler(&calls))
+
+			first := send(handler, "key-1", `{"name":"Fábio"}`)
+			retry := send(handler, "key-1", `{"name":"Fábio"}`)
+
+			if calls != 1 {
+				t.Errorf("Expected 1 call, returned %d", calls)
+			}
+			if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() || retry.Header().Get(HeaderReplayed) != "true" {
+				t.Errorf("Expected replayed %d %s, returned %d %s", first.Code, first.Body, retry.Code, retry.Body)
+			}
+			if retry.Header().Get("content-type") != "application/json" {
+				t.Errorf("Expected content-type to be replayed")
+			}
+
+			if w := send(handler, "key-1", `{"name":"Someone else"}`); w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Expected %d, returned %d", http.StatusUnprocessableEntity, w.Code)
+			}
+
+			send(handler, "key-2", `{"name":"Fábio"}`)
+			send(handler, "", `{"name":"Fábio"}`)
+			if calls != 3 {
+				t.Errorf("Expected 3 calls, returned %d", calls)
+			}
+		})
+	}
+}
+
+func TestMiddleware_DoesNotStoreServerErrors(t *testing.T) {
+	var calls int32
+	handler := Middleware(NewMemoryStore(time.Hour), func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	send(handler, "key-1", `{}`)
+	send(handler, "key-1", `{}`)
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, returned %d", calls)
+	}
+}
+
+func TestMiddleware_DoesNotStoreTransientStatuses(t *testing.T) {
+	for _, status := range []int{http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests} {
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			var calls int32
+			handler := Middleware(NewMemoryStore(time.Hour), func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					w.WriteHeader(status)
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+			})
+
+			send(handler, "key-1", `{}`)
+			w := send(handler, "key-1", `{}`)
+
+			if w.Code != http.StatusCreated || len(w.Header().Get(HeaderReplayed)) > 0 {
+				t.Errorf("Expected %d, returned %d replayed %q", http.StatusCreated, w.Code, w.Header().Get(HeaderReplayed))
+			}
+		})
+	}
+}
+
+func TestMiddleware_RetryAfterCancel(t *testing.T) {
+	var created int32
+	handler := Middleware(NewMemoryStore(time.Hour), func(w http.ResponseWriter, r *http.Request) {
+		if r.Context().Err() != nil {
+			w.WriteHeader(statusClientClosedRequest)
+			return
+		}
+		countingHandler(&created)(w, r)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := httptest.NewRequest(http.MethodPut, "/accounts", strings.NewReader(`{}`)).WithContext(ctx)
+	r.Header.Set(HeaderKey, "key-1")
+	handler(httptest.NewRecorder(), r)
+
+	w := send(handler, "key-1", `{}`)
+	if w.Code != http.StatusCreated || len(w.Header().Get(HeaderReplayed)) > 0 {
+		t.Errorf("Expected %d, returned %d replayed %q", http.StatusCreated, w.Code, w.Header().Get(HeaderReplayed))
+	}
+
+	w = send(handler, "key-1", `{}`)
+	if w.Code != http.StatusCreated || w.Header().Get(HeaderReplayed) != "true" {
+		t.Errorf("Expected the retry's response to be replayed, returned %d replayed %q", w.Code, w.Header().Get(HeaderReplayed))
+	}
+	if created != 1 {
+		t.Errorf("Expected 1 create, returned %d", created)
+	}
+}
+
+func TestStore_TTL(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for name, store := range map[string]Store{"Memory": NewMemoryStore(time.Minute), "File": fileStore} {
+		t.Run(name, func(t *testing.T) {
+			store.Put("old", Record{StatusCode: http.StatusCreated, CreatedAt: time.Now().Add(-2 * time.Minute)})
+			store.Put("new", Record{StatusCode: http.StatusCreated, CreatedAt: time.Now()})
+
+			if _, ok, _ := store.Get("old"); ok {
+				t.Errorf("Expected expired record to be gone")
+			}
+			if _, ok, _ := store.Get("new"); !ok {
+				t.Errorf("Expected record")
+			}
+		})
+	}
+}
+
+func TestStore_SweepsUnusedKeys(t *testing.T) {
+	memoryStore := NewMemoryStore(time.Minute)
+	fileStore, err := NewFileStore(t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	old := Record{StatusCode: http.StatusCreated, CreatedAt: time.Now().Add(-2 * time.Minute)}
+	memoryStore.Put("old", old)
+	fileStore.Put("old", old)
+
+	// not due yet: the sweep runs at most once per TTL
+	memoryStore.Put("new", Record{StatusCode: http.StatusCreated, CreatedAt: time.Now()})
+	if len(memoryStore.records) != 2 {
+		t.Errorf("Expected 2 records before the sweep, returned %d", len(memoryStore.records))
+	}
+
+	memoryStore.swept = time.Now().Add(-2 * time.Minute)
+	fileStore.swept = time.Now().Add(-2 * time.Minute)
+	memoryStore.Put("new", Record{StatusCode: http.StatusCreated, CreatedAt: time.Now()})
+	fileStore.Put("new", Record{StatusCode: http.StatusCreated, CreatedAt: time.Now()})
+
+	if _, ok := memoryStore.records["old"]; ok || len(memoryStore.records) != 1 {
+		t.Errorf("Expected only the new record in memory, returned %d records", len(memoryStore.records))
+	}
+	entries, _ := os.ReadDir(fileStore.dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the new record on disk, returned %d files", len(entries))
+	}
+}
@@ -0,0 +1,184 @@
+// Package idempotency replays the stored response of a request retried with
+// the same Idempotency-Key header.
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record is the first response given to an idempotency key.
+type Record struct {
+	RequestHash string    `json:"request_hash"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"content_type,omitempty"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Store keeps records until their TTL has passed. The stores below also drop
+// the expired records of keys that are never used again: every Put sweeps
+// them out, at most once per TTL.
+type Store interface {
+	Get(key string) (Record, bool, error)
+	Put(key string, record Record) error
+}
+
+// MemoryStore keeps records in memory, for a single gateway instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	records map[string]Record
+	swept   time.Time
+}
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, records: map[string]Record{}, swept: time.Now()}
+}
+
+func (s *MemoryStore) Get(key string) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if ok && expired(record, s.ttl) {
+		delete(s.records, key)
+		return Record{}, false, nil
+	}
+	return record, ok, nil
+}
+
+func (s *MemoryStore) Put(key string, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sweepDue(s.swept, s.ttl) {
+		s.swept = time.Now()
+		for key, record := range s.records {
+			if expired(record, s.ttl) {
+				delete(s.records, key)
+			}
+		}
+	}
+
+	s.records[key] = record
+	return nil
+}
+
+// FileStore keeps one JSON file per key in a directory, so records survive
+// restarts and can be shared by gateways mounting the same volume.
+type FileStore struct {
+	dir string
+	ttl time.Duration
+
+	mu    sync.Mutex
+	swept time.Time
+}
+
+func NewFileStore(dir string, ttl time.Duration) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir, ttl: ttl, swept: time.Now()}, nil
+}
+
+// path hashes the key, which is client supplied and not safe as a file name.
+func (s *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *FileStore) Get(key string) (Record, bool, error) {
+	recordJson, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return Record{}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(recordJson, &record); err != nil {
+		return Record{}, false, err
+	}
+
+	if expired(record, s.ttl) {
+		os.Remove(s.path(key))
+		return Record{}, false, nil
+	}
+	return record, true, nil
+}
+
+func (s *FileStore) Put(key string, record Record) error {
+	s.mu.Lock()
+	if sweepDue(s.swept, s.ttl) {
+		s.swept = time.Now()
+		s.sweep()
+	}
+	s.mu.Unlock()
+
+	recordJson, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	// write then rename, so a concurrent Get never reads half a record
+	tmp, err := os.CreateTemp(s.dir, "record-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(recordJson); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// sweep removes the expired records, and the temporary files older than the
+// TTL left by a gateway that stopped halfway through a Put. Files another
+// gateway removes in the meantime are skipped.
+func (s *FileStore) sweep() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(s.dir, entry.Name())
+		if strings.HasPrefix(entry.Name(), "record-") {
+			if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > s.ttl {
+				os.Remove(path)
+			}
+			continue
+		}
+
+		recordJson, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var record Record
+		if json.Unmarshal(recordJson, &record) == nil && expired(record, s.ttl) {
+			os.Remove(path)
+		}
+	}
+}
+
+func sweepDue(swept time.Time, ttl time.Duration) bool {
+	return ttl > 0 && time.Since(swept) >= ttl
+}
+
+func expired(record Record, ttl time.Duration) bool {
+	return ttl > 0 && time.Since(record.CreatedAt) > ttl
+}
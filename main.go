@@ -1,28 +1,126 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"io/ioutil"
+	"errors"
+	"expvar"
+	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/client-library/accounts"
+	"github.com/client-library/bankdir"
+	"github.com/client-library/deadline"
 	"github.com/client-library/domain"
+	"github.com/client-library/idempotency"
+	"github.com/client-library/modulus"
+)
+
+// retryStats counts the upstream retries, published on /debug/vars.
+var retryStats = accounts.NewRetryStats()
+
+var client = accounts.NewClient(append(clientOptionsFromEnv(), accounts.WithMetrics(retryStats))...)
+
+var URL = client.AccountsURL()
 
-	"github.com/google/uuid"
+const (
+	deleteModeAuto   = "auto"
+	deleteModeStrict = "strict"
 )
 
-const URL = "http://localhost:8080/v1/organisation/accounts"
+// deleteMode decides what Delete does when the caller sends no version:
+// "auto" deletes the current version, "strict" rejects the request with 428.
+var deleteMode = deleteModeFromEnv()
+
+func deleteModeFromEnv() string {
+	if os.Getenv("GATEWAY_DELETE_MODE") == deleteModeStrict {
+		return deleteModeStrict
+	}
+	return deleteModeAuto
+}
+
+// clientOptionsFromEnv loads the optional local datasets: the UK modulus weight
+// table named by MODULUS_WEIGHTS_FILE, with the exception 5 sort code
+// substitutions named by MODULUS_SUBSTITUTIONS_FILE, applied to creates too when
+// MODULUS_CHECK_ON_CREATE=true, and the bank directory named by BANK_DIRECTORY_FILE.
+func clientOptionsFromEnv() []accounts.Option {
+	var opts []accounts.Option
+
+	if path, ok := os.LookupEnv("MODULUS_WEIGHTS_FILE"); ok {
+		checker, err := modulus.LoadWeightsFile(path)
+		if substitutions, ok := os.LookupEnv("MODULUS_SUBSTITUTIONS_FILE"); ok && err == nil {
+			err = checker.LoadSubstitutionsFile(substitutions)
+		}
+		switch {
+		case err != nil:
+			log.Printf("modulus checking disabled: %v", err)
+		case os.Getenv("MODULUS_CHECK_ON_CREATE") == "true":
+			opts = append(opts, accounts.WithModulusCheckOnCreate(checker))
+		default:
+			opts = append(opts, accounts.WithModulusChecker(checker))
+		}
+	}
+
+	if path, ok := os.LookupEnv("BANK_DIRECTORY_FILE"); ok {
+		directory, err := bankdir.LoadFile(path)
+		if err != nil {
+			log.Printf("bank directory disabled: %v", err)
+		} else {
+			opts = append(opts, accounts.WithBankDirectory(directory))
+		}
+	}
+
+	return opts
+}
+
+// idempotencyStoreFromEnv keeps Idempotency-Key responses for IDEMPOTENCY_TTL
+// (default 24h), in IDEMPOTENCY_STORE_DIR when set and in memory otherwise.
+func idempotencyStoreFromEnv() idempotency.Store {
+	ttl := 24 * time.Hour
+	if value, ok := os.LookupEnv("IDEMPOTENCY_TTL"); ok {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			ttl = parsed
+		}
+	}
+
+	if dir, ok := os.LookupEnv("IDEMPOTENCY_STORE_DIR"); ok {
+		store, err := idempotency.NewFileStore(dir, ttl)
+		if err == nil {
+			return store
+		}
+		log.Printf("idempotency file store disabled: %v", err)
+	}
+
+	return idempotency.NewMemoryStore(ttl)
+}
+
+// accountsHandler serves /accounts with the request deadline and the
+// Idempotency-Key replays kept in store.
+func accountsHandler(store idempotency.Store) http.HandlerFunc {
+	return deadline.Middleware(idempotency.Middleware(store, ServeHTTP))
+}
 
 func ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("content-type", "application/json")
 	switch r.Method {
 	case http.MethodGet:
-		Fetch(w, r)
+		if r.URL.Query().Has("account_id") {
+			Fetch(w, r)
+			return
+		}
+		List(w, r)
 		return
 	case http.MethodPut:
 		Create(w, r)
 		return
+	case http.MethodPatch:
+		Update(w, r)
+		return
 	case http.MethodDelete:
 		Delete(w, r)
 		return
@@ -33,56 +131,62 @@ func ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func Fetch(w http.ResponseWriter, r *http.Request) {
-	c := http.Client{Timeout: time.Duration(1) * time.Second}
 	accountId := r.URL.Query().Get("account_id")
 
-	url := URL + "/" + accountId
-	response, err := c.Get(url)
-
+	result, err := client.Fetch(r.Context(), accountId)
 	if err != nil {
-		http.Error(w, err.Error(), response.StatusCode)
+		writeError(w, err)
 		return
 	}
 
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
+	w.Header().Set("ETag", `"`+strconv.FormatInt(result.Version, 10)+`"`)
+	writeJSON(w, http.StatusOK, result)
+}
 
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+func List(w http.ResponseWriter, r *http.Request) {
+	opts := accounts.ListOptions{Filter: accounts.FilterFromQuery(r.URL.Query())}
+	var err error
 
-	statusOK := response.StatusCode >= 200 && response.StatusCode < 300
-	if !statusOK {
-		var out bytes.Buffer
-		json.Indent(&out, body, "", "  ")
+	if pageNumber := r.URL.Query().Get("page[number]"); len(pageNumber) > 0 {
+		if opts.PageNumber, err = strconv.Atoi(pageNumber); err != nil || opts.PageNumber < 0 {
+			writeException(w, http.StatusBadRequest, "page[number] must be a non-negative integer: "+pageNumber)
+			return
+		}
+	}
+	if pageSize := r.URL.Query().Get("page[size]"); len(pageSize) > 0 {
+		if opts.PageSize, err = strconv.Atoi(pageSize); err != nil || opts.PageSize < 1 {
+			writeException(w, http.StatusBadRequest, "page[size] must be a positive integer: "+pageSize)
+			return
+		}
+	}
 
-		w.WriteHeader(response.StatusCode)
-		w.Write(out.Bytes())
+	result, err := client.List(r.Context(), opts)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
-	var backendResult domain.GetAccountByIdBackendResult
-	errUnmarshal := json.Unmarshal(body, &backendResult)
+	//point the links to the gateway instead of the account api
+	result.Links.First = gatewayLink(r, result.Links.First)
+	result.Links.Last = gatewayLink(r, result.Links.Last)
+	result.Links.Next = gatewayLink(r, result.Links.Next)
+	result.Links.Prev = gatewayLink(r, result.Links.Prev)
+	result.Links.Self = gatewayLink(r, result.Links.Self)
 
-	if errUnmarshal != nil {
-		http.Error(w, errUnmarshal.Error(), http.StatusInternalServerError)
-		return
-	}
+	writeJSON(w, http.StatusOK, result)
+}
 
-	//map
-	var result domain.GetAccountByIdResult
-	result.Attributes = backendResult.Data.Attributes
-	result.CreatedOn = backendResult.Data.CreatedOn
+func gatewayLink(r *http.Request, link string) string {
+	if len(link) == 0 {
+		return ""
+	}
 
-	jsonBytes, err := json.Marshal(result)
+	upstream, err := url.Parse(link)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return ""
 	}
 
-	w.WriteHeader(response.StatusCode)
-	w.Write(jsonBytes)
+	return r.URL.Path + "?" + upstream.RawQuery
 }
 
 func Create(w http.ResponseWriter, r *http.Request) {
@@ -90,127 +194,206 @@ func Create(w http.ResponseWriter, r *http.Request) {
 	requestBody := &domain.CreateAccountRequest{}
 	err := json.NewDecoder(r.Body).Decode(requestBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeException(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	requestBackend := &domain.CreateAccountBackendRequest{}
-	requestBackend.Data.ID = uuid.NewString()
-	requestBackend.Data.Type = "accounts"
-	requestBackend.Data.OrganisationID = requestBody.OrganisationID
-	requestBackend.Data.Attributes = requestBody.Attributes
+	if key := r.Header.Get("Idempotency-Key"); len(key) > 0 && len(requestBody.ID) == 0 {
+		requestBody.ID = accounts.IDFromIdempotencyKey(requestBody.OrganisationID, key)
+	}
 
-	accountJson, err := json.Marshal(requestBackend)
+	result, err := client.Create(r.Context(), *requestBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	response, err := http.Post(URL, "application/json", bytes.NewBuffer(accountJson))
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func Update(w http.ResponseWriter, r *http.Request) {
+	accountId := r.URL.Query().Get("account_id")
+	version := r.URL.Query().Get("version")
+
+	versionNumber, err := strconv.ParseInt(version, 10, 64)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeException(w, http.StatusBadRequest, "version must be an integer: "+version)
 		return
 	}
 
+	patch := &domain.AttributesPatch{}
+	err = json.NewDecoder(r.Body).Decode(patch)
 	if err != nil {
-		http.Error(w, err.Error(), response.StatusCode)
+		writeException(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
-
+	result, err := client.Update(r.Context(), accountId, versionNumber, *patch)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	statusOK := response.StatusCode >= 200 && response.StatusCode < 300
-	if !statusOK {
-		var out bytes.Buffer
-		json.Indent(&out, body, "", "  ")
+	writeJSON(w, http.StatusOK, result)
+}
 
-		w.WriteHeader(response.StatusCode)
-		w.Write(out.Bytes())
-		return
-	}
+func Delete(w http.ResponseWriter, r *http.Request) {
+	accountId := r.URL.Query().Get("account_id")
+	version := r.URL.Query().Get("version")
 
-	var backendResult domain.CreateAccountBackendResult
-	errUnmarshal := json.Unmarshal(body, &backendResult)
+	// If-Match: * asks for whatever version the account has, in both modes
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "*" {
+		version = ""
+	} else if len(ifMatch) > 0 {
+		version = strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	}
 
-	if errUnmarshal != nil {
-		http.Error(w, errUnmarshal.Error(), http.StatusInternalServerError)
+	if len(version) <= 0 {
+		if deleteMode == deleteModeStrict && ifMatch != "*" {
+			writeException(w, http.StatusPreconditionRequired, "version is required, send it as If-Match header or version parameter")
+			return
+		}
+
+		result, err := client.DeleteLatest(r.Context(), accountId)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
 		return
 	}
 
-	var result domain.CreateAccountResult
-	result.AccountId = backendResult.Data.ID
-	result.Attributes = backendResult.Data.Attributes
-	result.CreatedOn = backendResult.Data.CreatedOn
+	versionNumber, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		writeException(w, http.StatusBadRequest, "version must be an integer: "+version)
+		return
+	}
 
-	jsonBytes, err := json.Marshal(result)
+	result, err := client.Delete(r.Context(), accountId, versionNumber)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	w.WriteHeader(response.StatusCode)
-	w.Write(jsonBytes)
+	writeJSON(w, http.StatusOK, result)
 }
 
-func Delete(w http.ResponseWriter, r *http.Request) {
-	c := http.Client{Timeout: time.Duration(1) * time.Second}
-	accountId := r.URL.Query().Get("account_id")
-	version := r.URL.Query().Get("version")
-
-	if len(version) <= 0 {
-		version = "0"
+func ValidateUKAccount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
 	}
 
-	url := URL + "/" + accountId + "?version=" + version
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	sortCode := r.URL.Query().Get("sort_code")
+	accountNumber := r.URL.Query().Get("account_number")
+
+	result, err := client.CheckUKAccount(r.Context(), sortCode, accountNumber)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
-	response, err := c.Do(req)
 
-	if err != nil {
-		http.Error(w, err.Error(), response.StatusCode)
+	writeJSON(w, http.StatusOK, result)
+}
+
+func LookupBank(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
 		return
 	}
 
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
+	bic := strings.TrimPrefix(r.URL.Path, "/banks/")
+
+	banks, err := client.LookupBank(bic)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
 
-	statusOK := response.StatusCode >= 200 && response.StatusCode < 300
-	if !statusOK {
-		var out bytes.Buffer
-		json.Indent(&out, body, "", "  ")
+	writeJSON(w, http.StatusOK, banks)
+}
 
-		w.WriteHeader(response.StatusCode)
-		w.Write(out.Bytes())
+// Health reports whether the account API is reachable: 503 while the circuit
+// breaker is open, 200 otherwise, with the breaker state in the body.
+func Health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
 		return
 	}
-	var result domain.DeleteAccountResult
-	result.Message = "Account ID " + accountId + " removed with success"
-	result.Success = statusOK
 
-	jsonResult, err := json.Marshal(result)
+	state := client.BreakerState()
+	result := domain.HealthResult{Status: domain.HealthUp, CircuitBreaker: string(state)}
+	statusCode := http.StatusOK
+	switch state {
+	case accounts.BreakerOpen:
+		result.Status = domain.HealthDown
+		statusCode = http.StatusServiceUnavailable
+	case accounts.BreakerHalfOpen:
+		result.Status = domain.HealthDegraded
+	}
+
+	writeJSON(w, statusCode, result)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, result interface{}) {
+	jsonBytes, err := json.Marshal(result)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResult)
-	return
+	w.WriteHeader(statusCode)
+	w.Write(jsonBytes)
+}
+
+const statusClientClosedRequest = 499
+
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *accounts.APIError
+	if errors.As(err, &apiErr) {
+		writeJSON(w, apiErr.StatusCode, domain.CustomException{
+			ErrorMessage: apiErr.Message,
+			FieldErrors:  apiErr.FieldErrors})
+		return
+	}
+	var transportErr *accounts.TransportError
+	if errors.As(err, &transportErr) {
+		writeException(w, transportErr.StatusCode(), transportErr.Error())
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		// nobody reads this response, it only shows up in the access log
+		writeException(w, statusClientClosedRequest, err.Error())
+		return
+	}
+	if errors.Is(err, accounts.ErrCircuitOpen) {
+		writeException(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	writeException(w, http.StatusInternalServerError, err.Error())
+}
 
+func writeException(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, domain.CustomException{ErrorMessage: message})
 }
 
 func main() {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/accounts", ServeHTTP)
-	http.ListenAndServe("localhost:8081", mux)
+	mux.HandleFunc("/accounts", accountsHandler(idempotencyStoreFromEnv()))
+	mux.HandleFunc("/validations/gbdsc", deadline.Middleware(ValidateUKAccount))
+	mux.HandleFunc("/banks/", LookupBank)
+	mux.HandleFunc("/health", Health)
+	expvar.Publish("account_api_retries", expvar.Func(retryStats.Snapshot))
+	mux.Handle("/debug/vars", expvar.Handler())
+	addr, ok := os.LookupEnv("GATEWAY_ADDR")
+	if !ok {
+		addr = "localhost:8081"
+	}
+	http.ListenAndServe(addr, mux)
 }
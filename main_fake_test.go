@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/client-library/accounts"
+	"github.com/client-library/fakeaccountapi"
+)
+
+// TestMain runs the gateway tests against an in-memory account API, unless
+// ACCOUNT_API_BASE_URL points them at a real one such as the docker-compose stack.
+func TestMain(m *testing.M) {
+	if _, ok := os.LookupEnv("ACCOUNT_API_BASE_URL"); ok {
+		os.Exit(m.Run())
+	}
+
+	fake := fakeaccountapi.New()
+	server := httptest.NewServer(fake)
+	client = accounts.NewClient(append(clientOptionsFromEnv(), accounts.WithBaseURL(server.URL), accounts.WithMetrics(retryStats))...)
+	URL = client.AccountsURL()
+
+	code := m.Run()
+	// every test deletes what it created through a fixture.Registry
+	if leaked := len(fake.Accounts()); code == 0 && leaked > 0 {
+		fmt.Printf("%d accounts were left behind by the tests\n", leaked)
+		code = 1
+	}
+	server.Close()
+	os.Exit(code)
+}
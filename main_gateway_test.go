@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/client-library/accounts"
+	"github.com/client-library/bankdir"
+	"github.com/client-library/domain"
+	"github.com/client-library/fixture"
+	"github.com/client-library/idempotency"
+	"github.com/client-library/modulus"
+
+	"github.com/google/uuid"
+)
+
+// withClient points the gateway at another client for the test. Like
+// withDeleteMode, tests using it must not call t.Parallel.
+func withClient(t *testing.T, other *accounts.Client) {
+	previous := client
+	client = other
+	t.Cleanup(func() { client = previous })
+}
+
+// withDeleteMode switches the gateway's delete mode for the test. Tests using
+// it must not call t.Parallel, so the parallel tests never see the switch.
+func withDeleteMode(t *testing.T, mode string) {
+	previous := deleteMode
+	deleteMode = mode
+	t.Cleanup(func() { deleteMode = previous })
+}
+
+func TestDeleteAccount_Version(t *testing.T) {
+	var testCases = []struct {
+		name                 string
+		mode                 string
+		query                string
+		if_match             string
+		without_account_id   bool
+		expected_status_code int
+	}{
+		{"IfMatchCurrentVersion", deleteModeAuto, "", `"1"`, false, http.StatusOK},
+		{"IfMatchWeakETag", deleteModeAuto, "", `W/"1"`, false, http.StatusOK},
+		{"IfMatchStaleVersion", deleteModeAuto, "", `"0"`, false, http.StatusConflict},
+		{"IfMatchWinsOverParameter", deleteModeAuto, "&version=0", `"1"`, false, http.StatusOK},
+		{"IfMatchAnyVersion", deleteModeAuto, "", "*", false, http.StatusOK},
+		{"IfMatchNotAnInteger", deleteModeAuto, "", `"one"`, false, http.StatusBadRequest},
+		{"NoVersion", deleteModeAuto, "", "", false, http.StatusOK},
+		{"EmptyAccountId", deleteModeAuto, "", "", true, http.StatusBadRequest},
+		{"StrictNoVersion", deleteModeStrict, "", "", false, http.StatusPreconditionRequired},
+		{"StrictIfMatchAnyVersion", deleteModeStrict, "", "*", false, http.StatusOK},
+		{"StrictVersionParameter", deleteModeStrict, "&version=1", "", false, http.StatusOK}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withDeleteMode(t, tc.mode)
+			registry := fixture.NewRegistry(t, client)
+			ctx := context.Background()
+
+			created, err := registry.Create(ctx, createAccountRequest_Client)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			name := []string{"Fábio Moraes"}
+			if _, err := client.Update(ctx, created.AccountId, 0, domain.AttributesPatch{Name: &name}); err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			accountId := created.AccountId
+			if tc.without_account_id {
+				accountId = ""
+			}
+			r := httptest.NewRequest(http.MethodDelete, "/accounts?account_id="+accountId+tc.query, nil)
+			if len(tc.if_match) > 0 {
+				r.Header.Set("If-Match", tc.if_match)
+			}
+			w := httptest.NewRecorder()
+			ServeHTTP(w, r)
+
+			if w.Code != tc.expected_status_code {
+				t.Errorf("Expected %d, returned %d %s", tc.expected_status_code, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateAccount_Version(t *testing.T) {
+	t.Parallel()
+	var testCases = []struct {
+		name                 string
+		query                string
+		body                 string
+		expected_status_code int
+	}{
+		{"CurrentVersion", "&version=0", `{"name":["Fábio Moraes"]}`, http.StatusOK},
+		{"StaleVersion", "&version=1", `{"name":["Fábio Moraes"]}`, http.StatusConflict},
+		{"NoVersion", "", `{"name":["Fábio Moraes"]}`, http.StatusBadRequest},
+		{"VersionNotAnInteger", "&version=one", `{"name":["Fábio Moraes"]}`, http.StatusBadRequest},
+		{"BodyNotJSON", "&version=0", `{"name":`, http.StatusBadRequest},
+		{"BodyWrongType", "&version=0", `{"name":"Fábio Moraes"}`, http.StatusBadRequest}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := fixture.NewRegistry(t, client)
+
+			created, err := registry.Create(context.Background(), createAccountRequest_Client)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+
+			r := httptest.NewRequest(http.MethodPatch, "/accounts?account_id="+created.AccountId+tc.query, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			ServeHTTP(w, r)
+
+			if w.Code != tc.expected_status_code {
+				t.Errorf("Expected %d, returned %d %s", tc.expected_status_code, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestCreateAccount_IdempotencyKey(t *testing.T) {
+	var testCases = []struct {
+		name                 string
+		retry                domain.CreateAccountRequest
+		expected_status_code int
+		expected_replayed    bool
+	}{
+		{"SameRequest", createAccountRequest_Client, http.StatusCreated, true},
+		{"DifferentRequest", fixture.NewAccount().WithOrganisationID(organisationId).Build(), http.StatusUnprocessableEntity, false}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := fixture.NewRegistry(t, client)
+			handler := accountsHandler(idempotency.NewMemoryStore(time.Hour))
+			key := uuid.NewString()
+			accountId := accounts.IDFromIdempotencyKey(organisationId, key)
+			registry.Register(accountId)
+
+			put := func(request domain.CreateAccountRequest) (*httptest.ResponseRecorder, domain.CreateAccountResult) {
+				body, err := json.Marshal(request)
+				if err != nil {
+					t.Fatalf(err.Error())
+				}
+				r := httptest.NewRequest(http.MethodPut, "/accounts", bytes.NewReader(body))
+				r.Header.Set(idempotency.HeaderKey, key)
+				w := httptest.NewRecorder()
+				handler(w, r)
+
+				var result domain.CreateAccountResult
+				json.Unmarshal(w.Body.Bytes(), &result)
+				return w, result
+			}
+
+			w, created := put(createAccountRequest_Client)
+			if w.Code != http.StatusCreated || created.AccountId != accountId {
+				t.Fatalf("Expected %d for account %s, returned %d %s", http.StatusCreated, accountId, w.Code, w.Body)
+			}
+
+			w, retried := put(tc.retry)
+			if w.Code != tc.expected_status_code {
+				t.Errorf("Expected %d, returned %d %s", tc.expected_status_code, w.Code, w.Body)
+			}
+			if replayed := w.Header().Get(idempotency.HeaderReplayed) == "true"; replayed != tc.expected_replayed {
+				t.Errorf("Expected replayed %v, returned %v", tc.expected_replayed, replayed)
+			}
+			if tc.expected_replayed && retried.AccountId != created.AccountId {
+				t.Errorf("Expected account %s, returned %s", created.AccountId, retried.AccountId)
+			}
+		})
+	}
+}
+
+func TestHealth(t *testing.T) {
+	var testCases = []struct {
+		name                 string
+		cooldown             time.Duration
+		failures             int
+		wait                 time.Duration
+		expected_status_code int
+		expected_status      string
+		expected_breaker     accounts.BreakerState
+	}{
+		{"Closed", time.Hour, 0, 0, http.StatusOK, domain.HealthUp, accounts.BreakerClosed},
+		{"Open", time.Hour, 1, 0, http.StatusServiceUnavailable, domain.HealthDown, accounts.BreakerOpen},
+		{"HalfOpen", time.Millisecond, 1, 10 * time.Millisecond, http.StatusOK, domain.HealthDegraded, accounts.BreakerHalfOpen}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			t.Cleanup(upstream.Close)
+			withClient(t, accounts.NewClient(accounts.WithBaseURL(upstream.URL), accounts.WithRetryPolicy(accounts.NoRetry),
+				accounts.WithCircuitBreaker(accounts.NewCircuitBreaker(1, tc.cooldown))))
+
+			for i := 0; i < tc.failures; i++ {
+				client.Fetch(context.Background(), "50078af6-1b5e-11ed-861d-0242ac120002")
+			}
+			time.Sleep(tc.wait)
+
+			w := httptest.NewRecorder()
+			Health(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			var result domain.HealthResult
+			json.NewDecoder(w.Body).Decode(&result)
+			if w.Code != tc.expected_status_code || result.Status != tc.expected_status || result.CircuitBreaker != string(tc.expected_breaker) {
+				t.Errorf("Expected %d %s %s, returned %d %+v", tc.expected_status_code, tc.expected_status, tc.expected_breaker, w.Code, result)
+			}
+		})
+	}
+}
+
+func TestFetchAccount_UpstreamUnavailable(t *testing.T) {
+	var testCases = []struct {
+		name                 string
+		handler              http.HandlerFunc
+		expected_status_code int
+	}{
+		{"Timeout", func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}, http.StatusGatewayTimeout},
+		{"ConnectionClosed", func(w http.ResponseWriter, r *http.Request) {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+		}, http.StatusBadGateway}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upstream := httptest.NewServer(tc.handler)
+			t.Cleanup(upstream.Close)
+			withClient(t, accounts.NewClient(accounts.WithBaseURL(upstream.URL), accounts.WithRetryPolicy(accounts.NoRetry),
+				accounts.WithTimeout(50*time.Millisecond)))
+
+			r := httptest.NewRequest(http.MethodGet, "/accounts?account_id=50078af6-1b5e-11ed-861d-0242ac120002", nil)
+			w := httptest.NewRecorder()
+			ServeHTTP(w, r)
+
+			if w.Code != tc.expected_status_code {
+				t.Errorf("Expected %d, returned %d %s", tc.expected_status_code, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestListAccounts_Paging(t *testing.T) {
+	t.Parallel()
+	var testCases = []struct {
+		name                 string
+		query                string
+		expected_status_code int
+	}{
+		{"FirstPage", "page[number]=0&page[size]=1", http.StatusOK},
+		{"NegativePageNumber", "page[number]=-1", http.StatusBadRequest},
+		{"PageNumberNotAnInteger", "page[number]=one", http.StatusBadRequest},
+		{"ZeroPageSize", "page[size]=0", http.StatusBadRequest},
+		{"NegativePageSize", "page[size]=-1", http.StatusBadRequest}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/accounts?"+tc.query, nil)
+			w := httptest.NewRecorder()
+			List(w, r)
+
+			if w.Code != tc.expected_status_code {
+				t.Errorf("Expected %d, returned %d %s", tc.expected_status_code, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestValidateUKAccount(t *testing.T) {
+	checker, err := modulus.LoadWeightsFile("modulus/testdata/valacdos.txt")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var testCases = []struct {
+		name                 string
+		method               string
+		query                string
+		with_weights         bool
+		expected_status_code int
+		expected_result      domain.AccountValidationResult
+	}{
+		{"Valid", http.MethodGet, "sort_code=089999&account_number=66374958", true, http.StatusOK, domain.AccountValidationResult{Valid: true, Source: accounts.ValidationSourceLocal}},
+		{"Invalid", http.MethodGet, "sort_code=089999&account_number=66374959", true, http.StatusOK, domain.AccountValidationResult{Valid: false, Source: accounts.ValidationSourceLocal}},
+		{"SortCodeNotNumeric", http.MethodGet, "sort_code=08999X&account_number=66374958", true, http.StatusBadRequest, domain.AccountValidationResult{}},
+		{"AccountNumberTooShort", http.MethodGet, "sort_code=089999&account_number=6637495", true, http.StatusBadRequest, domain.AccountValidationResult{}},
+		{"WrongMethod", http.MethodPost, "sort_code=089999&account_number=66374958", true, http.StatusNotFound, domain.AccountValidationResult{}},
+		{"WeightsNotConfigured", http.MethodGet, "sort_code=089999&account_number=66374958", false, http.StatusOK, domain.AccountValidationResult{Valid: true, Source: accounts.ValidationSourceRemote}}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{}`))
+			}))
+			t.Cleanup(upstream.Close)
+			opts := []accounts.Option{accounts.WithBaseURL(upstream.URL), accounts.WithRetryPolicy(accounts.NoRetry)}
+			if tc.with_weights {
+				opts = append(opts, accounts.WithModulusChecker(checker))
+			}
+			withClient(t, accounts.NewClient(opts...))
+
+			w := httptest.NewRecorder()
+			ValidateUKAccount(w, httptest.NewRequest(tc.method, "/validations/gbdsc?"+tc.query, nil))
+
+			var result domain.AccountValidationResult
+			if w.Code == http.StatusOK {
+				json.NewDecoder(w.Body).Decode(&result)
+			}
+			if w.Code != tc.expected_status_code || result != tc.expected_result {
+				t.Errorf("Expected %d %+v, returned %d %+v", tc.expected_status_code, tc.expected_result, w.Code, result)
+			}
+		})
+	}
+}
+
+func TestLookupBank(t *testing.T) {
+	directory, err := bankdir.LoadFile("bankdir/testdata/banks.csv")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	var testCases = []struct {
+		name                 string
+		method               string
+		bic                  string
+		with_directory       bool
+		expected_status_code int
+		expected_banks       int
+	}{
+		{"Bic8", http.MethodGet, "NWBKGB22", true, http.StatusOK, 2},
+		{"Bic11", http.MethodGet, "NWBKGB2LXXX", true, http.StatusOK, 1},
+		{"BranchFallsBackToHeadOffice", http.MethodGet, "NWBKGB22ABC", true, http.StatusOK, 2},
+		{"UnknownBic", http.MethodGet, "BARCGB22", true, http.StatusNotFound, 0},
+		{"MalformedBic", http.MethodGet, "NWBK", true, http.StatusBadRequest, 0},
+		{"NoBic", http.MethodGet, "", true, http.StatusBadRequest, 0},
+		{"WrongMethod", http.MethodPost, "NWBKGB22", true, http.StatusNotFound, 0},
+		{"DirectoryNotConfigured", http.MethodGet, "NWBKGB22", false, http.StatusNotImplemented, 0}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []accounts.Option
+			if tc.with_directory {
+				opts = append(opts, accounts.WithBankDirectory(directory))
+			}
+			withClient(t, accounts.NewClient(opts...))
+
+			w := httptest.NewRecorder()
+			LookupBank(w, httptest.NewRequest(tc.method, "/banks/"+tc.bic, nil))
+
+			var banks []domain.Bank
+			if w.Code == http.StatusOK {
+				json.NewDecoder(w.Body).Decode(&banks)
+			}
+			if w.Code != tc.expected_status_code || len(banks) != tc.expected_banks {
+				t.Errorf("Expected %d with %d banks, returned %d %+v", tc.expected_status_code, tc.expected_banks, w.Code, banks)
+			}
+		})
+	}
+}
+
+func TestClientOptionsFromEnv_ModulusSubstitutions(t *testing.T) {
+	t.Setenv("MODULUS_WEIGHTS_FILE", "modulus/testdata/valacdos.txt")
+	t.Setenv("MODULUS_SUBSTITUTIONS_FILE", "modulus/testdata/scsubtab.txt")
+	checked := accounts.NewClient(clientOptionsFromEnv()...)
+
+	// 938600 only passes exception 5 as the substituted 938611
+	result, err := checked.CheckUKAccount(context.Background(), "938600", "42368003")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if !result.Valid || result.Source != accounts.ValidationSourceLocal {
+		t.Errorf("Expected a valid local result, returned %+v", result)
+	}
+}
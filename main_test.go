@@ -2,8 +2,8 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -13,14 +13,14 @@ import (
 	"time"
 
 	"github.com/client-library/domain"
+	"github.com/client-library/fixture"
 )
 
 //#region CreateTestCase
 var organisationId = "84385b9c-176d-11ed-861d-0242ac120002"
 
-var accountIds = []string{
-	"802052e6-182e-11ed-861d-0242ac120002",
-	"cc3a78fe-1785-11ed-861d-0242ac120002"}
+// invalidRequestAccountId is sent with requests the API rejects, so it is never created.
+var invalidRequestAccountId = "cc3a78fe-1785-11ed-861d-0242ac120002"
 
 var createAccountRequest_Client = domain.CreateAccountRequest{
 	OrganisationID: organisationId,
@@ -71,7 +71,6 @@ var createAccountRequest_ServiceAPI = domain.CreateAccountBackendRequest{
 	Data: domain.Data{
 		Type:           "accounts",
 		OrganisationID: organisationId,
-		ID:             accountIds[0],
 		Attributes: domain.Attributes{
 			Country: "GB",
 			Name: []string{
@@ -90,7 +89,7 @@ var testCasesCreate_ServiceAPI = []struct {
 	{"InvalidOrganisationID", domain.CreateAccountBackendRequest{
 		Data: domain.Data{
 			OrganisationID: "0d077184-ca1b-4583-a416-29c9a51cf6e",
-			ID:             accountIds[1],
+			ID:             invalidRequestAccountId,
 			Attributes:     createAccountRequest_ServiceAPI.Data.Attributes}},
 		http.StatusBadRequest, "organisation_id in body must be of type uuid: \"0d077184-ca1b-4583-a416-29c9a51cf6e\""},
 	{"InvalidAccountID", domain.CreateAccountBackendRequest{
@@ -101,28 +100,28 @@ var testCasesCreate_ServiceAPI = []struct {
 	{"InvalidType", domain.CreateAccountBackendRequest{
 		Data: domain.Data{
 			OrganisationID: createAccountRequest_ServiceAPI.Data.OrganisationID,
-			ID:             accountIds[1],
+			ID:             invalidRequestAccountId,
 			Type:           "acounts",
 			Attributes:     createAccountRequest_ServiceAPI.Data.Attributes}},
 		http.StatusBadRequest, "type in body should be one of [accounts]"},
 	{"CountryIsRequired", domain.CreateAccountBackendRequest{
 		Data: domain.Data{
 			OrganisationID: createAccountRequest_ServiceAPI.Data.OrganisationID,
-			ID:             accountIds[1],
+			ID:             invalidRequestAccountId,
 			Attributes: domain.Attributes{
 				Name: createAccountRequest_ServiceAPI.Data.Attributes.Name}}},
 		http.StatusBadRequest, "country in body is required"},
 	{"NameIsRequired", domain.CreateAccountBackendRequest{
 		Data: domain.Data{
 			OrganisationID: createAccountRequest_ServiceAPI.Data.OrganisationID,
-			ID:             accountIds[1],
+			ID:             invalidRequestAccountId,
 			Attributes: domain.Attributes{
 				Country: createAccountRequest_ServiceAPI.Data.Attributes.Country}}},
 		http.StatusBadRequest, "name in body is required"},
 	{"CountryNotMatches", domain.CreateAccountBackendRequest{
 		Data: domain.Data{
 			OrganisationID: createAccountRequest_ServiceAPI.Data.OrganisationID,
-			ID:             accountIds[1],
+			ID:             invalidRequestAccountId,
 			Attributes: domain.Attributes{
 				Name:    createAccountRequest_ServiceAPI.Data.Attributes.Name,
 				Country: "B"}}},
@@ -130,7 +129,7 @@ var testCasesCreate_ServiceAPI = []struct {
 	{"NameMoreThan140CharsIsInvalid", domain.CreateAccountBackendRequest{
 		Data: domain.Data{
 			OrganisationID: createAccountRequest_ServiceAPI.Data.OrganisationID,
-			ID:             accountIds[1],
+			ID:             invalidRequestAccountId,
 			Attributes: domain.Attributes{
 				Name: []string{MockingMaxLengthString(140)}}}},
 		http.StatusBadRequest, "in body should be at most 140 chars long"}}
@@ -145,7 +144,7 @@ var testCasesFetch = []struct {
 	expected_status_code   int
 	expected_message_error string
 }{
-	{"ShouldReturnAccountInformation", accountIds[0], http.StatusOK, ""},
+	{"ShouldReturnAccountInformation", "", http.StatusOK, ""},
 	{"AccountDoesNotExist", "50078af6-1b5e-11ed-861d-0242ac120002", http.StatusNotFound, "record 50078af6-1b5e-11ed-861d-0242ac120002 does not exist"}}
 
 //#endregion
@@ -167,12 +166,26 @@ var testCasesDelete_ServiceAPI = []struct {
 	expected_status_code   int
 	expected_message_error string
 }{
-	{"ShouldReturnNoContentOnDeleteSuccessfully", accountIds[0], http.StatusNoContent, ""},
+	{"ShouldReturnNoContentOnDeleteSuccessfully", "", http.StatusNoContent, ""},
 	{"AccountNotFound", "50078af6-1b5e-11ed-861d-0242ac120002", http.StatusNotFound, ""}}
 
 //#endregion
 
+// createTestAccount creates an account deleted when the test finishes, for the
+// test cases without an account_id.
+func createTestAccount(t *testing.T, registry *fixture.Registry) string {
+	t.Helper()
+
+	created, err := registry.Create(context.Background(), createAccountRequest_Client)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	return created.AccountId
+}
+
 func TestCreateAccount_ClientLibrary(t *testing.T) {
+	t.Parallel()
+	registry := fixture.NewRegistry(t, client)
 
 	for _, tc := range testCasesCreate_Client {
 		t.Run("Client_"+tc.name, func(t *testing.T) {
@@ -226,19 +239,27 @@ func TestCreateAccount_ClientLibrary(t *testing.T) {
 				return
 			}
 
-			accountIds = append(accountIds, result.AccountId)
+			registry.Register(result.AccountId)
 
 		})
 	}
 }
 
 func TestCreateAccount_ServiceAPI(t *testing.T) {
+	t.Parallel()
+	registry := fixture.NewRegistry(t, client)
+	accountId := registry.ID()
 
 	for _, tc := range testCasesCreate_ServiceAPI {
 		t.Run("ServiceAPI_"+tc.name, func(t *testing.T) {
 
+			request := tc.request
+			if len(request.Data.ID) == 0 {
+				request.Data.ID = accountId
+			}
+
 			var buf bytes.Buffer
-			err := json.NewEncoder(&buf).Encode(tc.request)
+			err := json.NewEncoder(&buf).Encode(request)
 			if err != nil {
 				t.Errorf(err.Error())
 				return
@@ -283,11 +304,18 @@ func TestCreateAccount_ServiceAPI(t *testing.T) {
 }
 
 func TestFetchAccount_ClientLibrary(t *testing.T) {
+	t.Parallel()
+	accountId := createTestAccount(t, fixture.NewRegistry(t, client))
 
 	for _, tc := range testCasesFetch {
 		t.Run("Client_"+tc.name, func(t *testing.T) {
 
-			r := httptest.NewRequest(http.MethodGet, "/accounts?account_id="+tc.account_id, nil)
+			id := tc.account_id
+			if len(id) == 0 {
+				id = accountId
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/accounts?account_id="+id, nil)
 			w := httptest.NewRecorder()
 			ServeHTTP(w, r)
 
@@ -337,11 +365,18 @@ func TestFetchAccount_ClientLibrary(t *testing.T) {
 }
 
 func TestFetchAccount_ServiceAPI(t *testing.T) {
+	t.Parallel()
+	accountId := createTestAccount(t, fixture.NewRegistry(t, client))
 
 	for _, tc := range testCasesFetch {
 		t.Run("ServiceAPI_"+tc.name, func(t *testing.T) {
 
-			response, err := http.Get(URL + "/" + tc.account_id)
+			id := tc.account_id
+			if len(id) == 0 {
+				id = accountId
+			}
+
+			response, err := http.Get(URL + "/" + id)
 
 			if err != nil {
 				t.Errorf(err.Error())
@@ -394,11 +429,18 @@ func TestFetchAccount_ServiceAPI(t *testing.T) {
 }
 
 func TestDeleteAccount_ClientLibrary(t *testing.T) {
+	t.Parallel()
+	accountId := createTestAccount(t, fixture.NewRegistry(t, client))
 
 	for _, tc := range testCasesDelete_Client {
 		t.Run("Client_"+tc.name, func(t *testing.T) {
 
-			r := httptest.NewRequest(http.MethodDelete, "/accounts?account_id="+accountIds[len(accountIds)-1], nil)
+			id := tc.account_id
+			if len(id) == 0 {
+				id = accountId
+			}
+
+			r := httptest.NewRequest(http.MethodDelete, "/accounts?account_id="+id, nil)
 			w := httptest.NewRecorder()
 			ServeHTTP(w, r)
 
@@ -435,12 +477,19 @@ func TestDeleteAccount_ClientLibrary(t *testing.T) {
 }
 
 func TestDeleteAccount_ServiceAPI(t *testing.T) {
+	t.Parallel()
+	accountId := createTestAccount(t, fixture.NewRegistry(t, client))
 
 	c := http.Client{Timeout: time.Duration(1) * time.Second}
 	for _, tc := range testCasesDelete_ServiceAPI {
 		t.Run("ServiceAPI_"+tc.name, func(t *testing.T) {
 
-			url := URL + "/" + tc.account_id + "?version=0"
+			id := tc.account_id
+			if len(id) == 0 {
+				id = accountId
+			}
+
+			url := URL + "/" + id + "?version=0"
 			req, err := http.NewRequest(http.MethodDelete, url, nil)
 			if err != nil {
 				t.Errorf(err.Error())
@@ -481,6 +530,8 @@ func TestDeleteAccount_ServiceAPI(t *testing.T) {
 }
 
 func TestIntegratedAccount_ClientLibrary(t *testing.T) {
+	t.Parallel()
+	registry := fixture.NewRegistry(t, client)
 
 	var buf bytes.Buffer
 	//CREATE
@@ -508,11 +559,11 @@ func TestIntegratedAccount_ClientLibrary(t *testing.T) {
 		return
 	}
 
-	accountIds = append(accountIds, result.AccountId)
+	registry.Register(result.AccountId)
 
 	//FECTH
 	var tc2 = testCasesFetch[0]
-	r2 := httptest.NewRequest(http.MethodGet, "/accounts?account_id="+accountIds[len(accountIds)-1], nil)
+	r2 := httptest.NewRequest(http.MethodGet, "/accounts?account_id="+result.AccountId, nil)
 	w2 := httptest.NewRecorder()
 	ServeHTTP(w2, r2)
 
@@ -523,7 +574,7 @@ func TestIntegratedAccount_ClientLibrary(t *testing.T) {
 
 	//DELETE
 	var tc3 = testCasesDelete_Client[0]
-	r3 := httptest.NewRequest(http.MethodDelete, "/accounts?account_id="+accountIds[len(accountIds)-1], nil)
+	r3 := httptest.NewRequest(http.MethodDelete, "/accounts?account_id="+result.AccountId, nil)
 	w3 := httptest.NewRecorder()
 	ServeHTTP(w3, r3)
 
@@ -535,12 +586,16 @@ func TestIntegratedAccount_ClientLibrary(t *testing.T) {
 }
 
 func TestIntegratedAccount_ServiceAPI(t *testing.T) {
+	t.Parallel()
+	registry := fixture.NewRegistry(t, client)
 
 	//CREATE
 	var tc = testCasesCreate_ServiceAPI[0]
+	request := tc.request
+	request.Data.ID = registry.ID()
 
 	var buf bytes.Buffer
-	err := json.NewEncoder(&buf).Encode(tc.request)
+	err := json.NewEncoder(&buf).Encode(request)
 	if err != nil {
 		t.Errorf(err.Error())
 		return
@@ -560,7 +615,7 @@ func TestIntegratedAccount_ServiceAPI(t *testing.T) {
 	}
 
 	//FETCH
-	response2, err := http.Get(URL + "/" + tc.request.Data.ID)
+	response2, err := http.Get(URL + "/" + request.Data.ID)
 
 	if err != nil {
 		t.Errorf(err.Error())
@@ -575,7 +630,7 @@ func TestIntegratedAccount_ServiceAPI(t *testing.T) {
 
 	//DELETE
 	c := http.Client{Timeout: time.Duration(1) * time.Second}
-	url := URL + "/" + tc.request.Data.ID + "?version=0"
+	url := URL + "/" + request.Data.ID + "?version=0"
 	req, err := http.NewRequest(http.MethodDelete, url, nil)
 	if err != nil {
 		t.Errorf(err.Error())
@@ -589,21 +644,6 @@ func TestIntegratedAccount_ServiceAPI(t *testing.T) {
 	}
 }
 
-func Test_TearDown(t *testing.T) {
-	//CLEANING CREATED ACCOUNTS
-	for _, accountId := range accountIds {
-		url := URL + "/" + accountId + "?version=0"
-		c := http.Client{Timeout: time.Duration(1) * time.Second}
-
-		req, err := http.NewRequest(http.MethodDelete, url, nil)
-		response, err := c.Do(req)
-		if err != nil || response.StatusCode != http.StatusNoContent {
-			continue
-		}
-		fmt.Printf("Tear Down: accountId %v successfully removed \n", accountId)
-	}
-}
-
 func MockingMaxLengthString(maxLength int) string {
 	var str = ""
 
@@ -0,0 +1,290 @@
+// Package modulus implements the VocaLink modulus checks of UK sort codes and
+// account numbers, driven by the valacdos.txt weight table.
+// See https://www.vocalink.com/tools/modulus-checking/
+package modulus
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Method is the algorithm a weight table row applies.
+type Method string
+
+const (
+	MOD10 Method = "MOD10"
+	MOD11 Method = "MOD11"
+	DBLAL Method = "DBLAL"
+)
+
+var ErrFormat = errors.New("modulus: sort code must have 6 digits and account number 8 digits")
+
+// Weight is one row of the weight table: the weights of the 14 digits
+// u v w x y z a b c d e f g h of the sort code and account number.
+type Weight struct {
+	From      string
+	To        string
+	Method    Method
+	Weights   [14]int
+	Exception int
+}
+
+// Checker holds a loaded weight table and the optional sort code substitution
+// table used by exception 5.
+type Checker struct {
+	weights       []Weight
+	substitutions map[string]string
+}
+
+var (
+	sortCodePattern      = regexp.MustCompile(`^[0-9]{6}$`)
+	accountNumberPattern = regexp.MustCompile(`^[0-9]{8}$`)
+)
+
+// LoadWeights reads a weight table in the valacdos.txt format:
+//
+//	070116 070116 MOD11 0 0 7 6 5 8 7 6 5 4 3 2 1 0 12
+//
+// The 18th column, the exception, is optional.
+func LoadWeights(r io.Reader) (*Checker, error) {
+	checker := &Checker{substitutions: map[string]string{}}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 17 && len(fields) != 18 {
+			return nil, fmt.Errorf("modulus: weight table line %d: expected 17 or 18 columns, found %d", line, len(fields))
+		}
+
+		weight := Weight{From: fields[0], To: fields[1], Method: Method(fields[2])}
+		if weight.Method != MOD10 && weight.Method != MOD11 && weight.Method != DBLAL {
+			return nil, fmt.Errorf("modulus: weight table line %d: unknown method %s", line, fields[2])
+		}
+		for i := 0; i < 14; i++ {
+			value, err := strconv.Atoi(fields[3+i])
+			if err != nil {
+				return nil, fmt.Errorf("modulus: weight table line %d: %w", line, err)
+			}
+			weight.Weights[i] = value
+		}
+		if len(fields) == 18 {
+			exception, err := strconv.Atoi(fields[17])
+			if err != nil {
+				return nil, fmt.Errorf("modulus: weight table line %d: %w", line, err)
+			}
+			weight.Exception = exception
+		}
+
+		checker.weights = append(checker.weights, weight)
+	}
+
+	return checker, scanner.Err()
+}
+
+// LoadWeightsFile reads the weight table at path, see LoadWeights.
+func LoadWeightsFile(path string) (*Checker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadWeights(file)
+}
+
+// LoadSubstitutions reads the scsubtab.txt table of sort codes replaced by
+// exception 5, one "original substitute" pair per line.
+func (ch *Checker) LoadSubstitutions(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 {
+			ch.substitutions[fields[0]] = fields[1]
+		}
+	}
+	return scanner.Err()
+}
+
+// LoadSubstitutionsFile reads the substitution table at path, see LoadSubstitutions.
+func (ch *Checker) LoadSubstitutionsFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return ch.LoadSubstitutions(file)
+}
+
+// rows returns the weight table rows covering the sort code, in table order.
+func (ch *Checker) rows(sortCode string) []Weight {
+	var rows []Weight
+	for _, weight := range ch.weights {
+		if weight.From <= sortCode && sortCode <= weight.To {
+			rows = append(rows, weight)
+		}
+	}
+	return rows
+}
+
+// Check reports whether the account number is valid for the sort code.
+// Sort codes that are not in the weight table cannot be checked and are valid.
+func (ch *Checker) Check(sortCode string, accountNumber string) (bool, error) {
+	sortCode = strings.ReplaceAll(sortCode, "-", "")
+	if !sortCodePattern.MatchString(sortCode) || !accountNumberPattern.MatchString(accountNumber) {
+		return false, ErrFormat
+	}
+
+	rows := ch.rows(sortCode)
+	if len(rows) == 0 {
+		return true, nil
+	}
+
+	digits := toDigits(sortCode + accountNumber)
+	first := rows[0]
+
+	// exception 6: foreign currency accounts cannot be checked
+	if first.Exception == 6 && digits[a] >= 4 && digits[a] <= 8 && digits[g] == digits[h] {
+		return true, nil
+	}
+
+	firstValid := ch.run(first, digits)
+
+	if first.Exception == 2 && len(rows) > 1 {
+		// exceptions 2 & 9: an account passing the first check is valid
+		// without the second one
+		if firstValid {
+			return true, nil
+		}
+		second := rows[1]
+		if ch.run(second, digits) {
+			return true, nil
+		}
+		// exception 9: the second check is run again as if the account was
+		// held at sort code 309634
+		return ch.run(second, toDigits("309634"+accountNumber)), nil
+	}
+
+	if first.Exception == 14 && !firstValid {
+		if digits[h] != 0 && digits[h] != 1 && digits[h] != 9 {
+			return false, nil
+		}
+		return ch.run(first, toDigits(sortCode+"0"+accountNumber[:7])), nil
+	}
+
+	if len(rows) == 1 {
+		return firstValid, nil
+	}
+
+	second := rows[1]
+	// exceptions 10 & 11 and 12 & 13: either check passing is enough
+	if (first.Exception == 10 && second.Exception == 11) || (first.Exception == 12 && second.Exception == 13) {
+		return firstValid || ch.run(second, digits), nil
+	}
+	// exception 3: the second check is skipped when c is 6 or 9
+	if second.Exception == 3 && (digits[c] == 6 || digits[c] == 9) {
+		return firstValid, nil
+	}
+
+	return firstValid && ch.run(second, digits), nil
+}
+
+// Digit positions of the sort code (u..z) and account number (a..h).
+const (
+	u = iota
+	v
+	w
+	x
+	y
+	z
+	a
+	b
+	c
+	d
+	e
+	f
+	g
+	h
+)
+
+func toDigits(value string) [14]int {
+	var digits [14]int
+	for i := 0; i < 14 && i < len(value); i++ {
+		digits[i] = int(value[i] - '0')
+	}
+	return digits
+}
+
+// run applies a single weight table row, including its exception.
+func (ch *Checker) run(row Weight, digits [14]int) bool {
+	weights := row.Weights
+
+	switch row.Exception {
+	case 2:
+		if digits[a] != 0 && digits[g] != 9 {
+			weights = [14]int{0, 0, 1, 2, 5, 3, 6, 4, 8, 7, 10, 9, 3, 1}
+		}
+		if digits[a] != 0 && digits[g] == 9 {
+			weights = [14]int{0, 0, 0, 0, 0, 0, 0, 0, 8, 7, 10, 9, 3, 1}
+		}
+	case 5:
+		sortCode := digitsString(digits[:6])
+		if substitute, ok := ch.substitutions[sortCode]; ok {
+			substituted := toDigits(substitute + digitsString(digits[6:]))
+			digits = substituted
+		}
+	case 7, 10:
+		if row.Exception == 7 && digits[g] == 9 ||
+			row.Exception == 10 && digits[g] == 9 && (digits[a] == 0 || digits[a] == 9) && digits[b] == 9 {
+			for i := u; i <= b; i++ {
+				weights[i] = 0
+			}
+		}
+	case 8:
+		digits = toDigits("090126" + digitsString(digits[6:]))
+	}
+
+	total := 0
+	for i := 0; i < 14; i++ {
+		product := digits[i] * weights[i]
+		if row.Method == DBLAL {
+			product = product/10 + product%10
+		}
+		total += product
+	}
+	if row.Method == DBLAL && row.Exception == 1 {
+		total += 27
+	}
+
+	switch {
+	case row.Exception == 4:
+		return total%11 == digits[g]*10+digits[h]
+	case row.Exception == 5 && row.Method == MOD11:
+		remainder := total % 11
+		return remainder == 0 && digits[g] == 0 || remainder > 1 && 11-remainder == digits[g]
+	case row.Exception == 5 && row.Method == DBLAL:
+		remainder := total % 10
+		return remainder == 0 && digits[h] == 0 || remainder > 0 && 10-remainder == digits[h]
+	case row.Method == MOD11:
+		return total%11 == 0
+	default:
+		return total%10 == 0
+	}
+}
+
+func digitsString(digits []int) string {
+	var s strings.Builder
+	for _, digit := range digits {
+		s.WriteByte(byte('0' + digit))
+	}
+	return s.String()
+}
@@ -0,0 +1,125 @@
+package modulus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testdata/valacdos.txt holds the VocaLink weight table rows, and
+// testdata/scsubtab.txt the substitution, of the sort codes used by the test
+// vectors published in the specification; testCasesCheck are those vectors
+// as published.
+func loadTestWeights(t *testing.T) *Checker {
+	checker, err := LoadWeightsFile("testdata/valacdos.txt")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := checker.LoadSubstitutionsFile("testdata/scsubtab.txt"); err != nil {
+		t.Fatalf(err.Error())
+	}
+	return checker
+}
+
+var testCasesCheck = []struct {
+	name           string
+	sort_code      string
+	account_number string
+	expected_valid bool
+}{
+	{"StandardModulus10", "089999", "66374958", true},
+	{"StandardModulus11", "107999", "88837491", true},
+	{"StandardModulus10Fails", "089999", "66374959", false},
+	{"StandardModulus11Fails", "107999", "88837493", false},
+	{"Exception4RemainderIsCheckDigits", "134020", "63849203", true},
+	{"Exception5Passes", "938611", "07806039", true},
+	{"Exception5PassesWithSubstitution", "938600", "42368003", true},
+	{"Exception5BothRemaindersAre0", "938063", "55065200", true},
+	{"Exception5SecondCheckDigitIncorrect", "938063", "15764273", false},
+	{"Exception5FirstCheckDigitIncorrect", "938063", "15764264", false},
+	{"Exception5RemainderIs1", "938063", "15763217", false},
+	{"Exception7GIs9ZeroesWeights", "772798", "99345694", true}}
+
+func TestChecker_Check(t *testing.T) {
+	checker := loadTestWeights(t)
+
+	for _, tc := range testCasesCheck {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, err := checker.Check(tc.sort_code, tc.account_number)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			if valid != tc.expected_valid {
+				t.Errorf("Expected %v, returned %v", tc.expected_valid, valid)
+			}
+		})
+	}
+}
+
+// testdata/exceptions.txt is made up. Its rows reach the branches of the
+// exceptions the published vectors above do not. The 309070 rows only serve
+// the two published exception 2 vectors whose first check substitutes every
+// weight, so they pass whatever the table holds.
+var testCasesCheckExceptions = []struct {
+	name           string
+	sort_code      string
+	account_number string
+	expected_valid bool
+}{
+	{"SortCodeWithDashes", "20-00-50", "10087109", true},
+	{"SortCodeNotInTable", "999999", "12345678", true},
+	{"BothChecksPass", "200050", "10087109", true},
+	{"SecondCheckFails", "200050", "10047514", false},
+	{"Exception1", "300001", "10015838", true},
+	{"Exception2FirstCheckPasses", "300100", "12345675", true},
+	{"Exception9SecondCheckPassesWithSubstitution", "300100", "12345673", true},
+	{"Exception2And9AllChecksFail", "300100", "12345676", false},
+	{"Exception2GIsNot9", "309070", "12345677", true},
+	{"Exception2GIs9", "309070", "99345694", true},
+	{"Exception3SkipsSecondCheck", "500001", "10601844", true},
+	{"Exception3RunsSecondCheck", "500001", "10023757", false},
+	{"Exception6ForeignCurrency", "800001", "40092200", true},
+	{"Exception8SubstitutesSortCode", "900050", "12345675", true},
+	{"Exception8Fails", "900050", "12345676", false},
+	{"Exception10And11SecondPasses", "600001", "10039595", true},
+	{"Exception10And11BothFail", "600001", "10000000", false},
+	{"Exception14ShiftsAccountNumber", "700001", "10308841", true}}
+
+func TestChecker_CheckExceptions(t *testing.T) {
+	checker, err := LoadWeightsFile("testdata/exceptions.txt")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, tc := range testCasesCheckExceptions {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, err := checker.Check(tc.sort_code, tc.account_number)
+			if err != nil {
+				t.Fatalf(err.Error())
+			}
+			if valid != tc.expected_valid {
+				t.Errorf("Expected %v, returned %v", tc.expected_valid, valid)
+			}
+		})
+	}
+}
+
+func TestChecker_CheckInvalidFormat(t *testing.T) {
+	checker := loadTestWeights(t)
+
+	if _, err := checker.Check("0899", "66374958"); !errors.Is(err, ErrFormat) {
+		t.Errorf("Expected %v, returned %v", ErrFormat, err)
+	}
+	if _, err := checker.Check("089999", "6637495"); !errors.Is(err, ErrFormat) {
+		t.Errorf("Expected %v, returned %v", ErrFormat, err)
+	}
+}
+
+func TestLoadWeights_InvalidTable(t *testing.T) {
+	if _, err := LoadWeights(strings.NewReader("089000 089999 MOD12 0 0 0 0 0 0 7 1 3 7 1 3 7 1")); err == nil {
+		t.Errorf("Expected unknown method error")
+	}
+	if _, err := LoadWeights(strings.NewReader("089000 089999 MOD10 0 0 0")); err == nil {
+		t.Errorf("Expected column count error")
+	}
+}